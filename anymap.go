@@ -0,0 +1,32 @@
+package gocollections
+
+import "time"
+
+/*
+Any Map
+*/
+
+// AnyMap is a TimeExpiredMap which holds values of different types. Values can be retrieved with type safety via
+// GetAs function.
+type AnyMap[K comparable] interface {
+	TimeExpiredMap[K, any]
+}
+
+// NewAnyMap creates new AnyMap object. It runs goroutine for removing expired elements.
+func NewAnyMap[K comparable](duration time.Duration, configs ...Config) AnyMap[K] {
+	return NewTimeExpiredMap[K, any](duration, configs...)
+}
+
+// GetAs returns element by key converted to type T. If the element is not of type T, it returns ErrTypeMismatch.
+func GetAs[T any, K comparable](m AnyMap[K], key K) (T, error) {
+	var result T
+	val, err := m.Get(key)
+	if err != nil {
+		return result, err
+	}
+	result, ok := val.(T)
+	if !ok {
+		return result, ErrTypeMismatch
+	}
+	return result, nil
+}
@@ -0,0 +1,43 @@
+package gocollections
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAnyMap_GetAs(t *testing.T) {
+	t.Parallel()
+
+	amap := NewAnyMap[string](10 * time.Second)
+	defer amap.Discard()
+
+	amap.Add("name", "test 1")
+	amap.Add("count", 5)
+
+	name, err := GetAs[string](amap, "name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "test 1" {
+		t.Errorf("want: %s, got: %s", "test 1", name)
+	}
+
+	count, err := GetAs[int](amap, "count")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 5 {
+		t.Errorf("want: %d, got: %d", 5, count)
+	}
+
+	_, err = GetAs[int](amap, "name")
+	if !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("Expect ErrTypeMismatch but got %v", err)
+	}
+
+	_, err = GetAs[int](amap, "missing")
+	if !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Expect ErrKeyNotFound but got %v", err)
+	}
+}
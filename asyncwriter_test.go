@@ -0,0 +1,124 @@
+package gocollections
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAsyncWriter(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[int, int](time.Minute)
+	defer tmap.Discard()
+	w := NewAsyncWriter[int, int](tmap, time.Hour, 3)
+
+	_ = w.Add(1, 1)
+	_ = w.Add(2, 2)
+	if tmap.Size() != 0 {
+		t.Errorf("elements should be buffered, size: %d", tmap.Size())
+	}
+
+	// Full buffer is flushed by the goroutine.
+	_ = w.Add(3, 3)
+	for i := 0; i < 100 && tmap.Size() != 3; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if tmap.Size() != 3 {
+		t.Errorf("want: %d, got: %d", 3, tmap.Size())
+	}
+
+	_ = w.AddWithDuration(4, 4, time.Second)
+	w.Close()
+	if v, err := tmap.Get(4); err != nil || v != 4 {
+		t.Errorf("buffer should be flushed by Close, got: %d, %v", v, err)
+	}
+	if err := w.Add(5, 5); !errors.Is(err, ErrClosed) {
+		t.Errorf("want: %v, got: %v", ErrClosed, err)
+	}
+}
+
+func TestAsyncWriter_Striped(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[int, int](time.Minute)
+	defer tmap.Discard()
+	w := NewAsyncWriter[int, int](tmap, time.Hour, 0)
+	defer w.Close()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				_ = w.Add(g*100+i, i)
+			}
+		}(g)
+	}
+	wg.Wait()
+	// Writes of the same key spread over stripes are flushed in order of writes.
+	for i := 0; i < 100; i++ {
+		_ = w.Add(-1, i)
+	}
+	w.Flush()
+
+	if size := tmap.Size(); size != 801 {
+		t.Errorf("want: %d, got: %d", 801, size)
+	}
+	if v, _ := tmap.Get(-1); v != 99 {
+		t.Errorf("want: %d, got: %d", 99, v)
+	}
+}
+
+func TestCoalescingWriter(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, int](time.Minute)
+	defer tmap.Discard()
+	var dropped []int
+	w := NewCoalescingWriter[string, int](tmap, time.Hour, func(key string, value int) {
+		dropped = append(dropped, value)
+	})
+
+	for i := 1; i <= 3; i++ {
+		_ = w.Add("status", i)
+	}
+	_ = w.Add("other", 10)
+	w.Flush()
+	_ = w.Add("status", 4)
+	w.Close()
+
+	if v, err := tmap.Get("status"); err != nil || v != 4 {
+		t.Errorf("want: %d, got: %d, %v", 4, v, err)
+	}
+	if v, err := tmap.Get("other"); err != nil || v != 10 {
+		t.Errorf("want: %d, got: %d, %v", 10, v, err)
+	}
+	if len(dropped) != 2 || dropped[0] != 1 || dropped[1] != 2 {
+		t.Errorf("want: %v, got: %v", []int{1, 2}, dropped)
+	}
+}
+
+func TestAsyncWriter_InvalidInterval(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[int, int](time.Minute)
+	defer tmap.Discard()
+
+	constructors := map[string]func(){
+		"async":      func() { NewAsyncWriter(tmap, 0, 10) },
+		"coalescing": func() { NewCoalescingWriter[int, int](tmap, -time.Second, nil) },
+	}
+	for name, create := range constructors {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expect panic of %s writer with not positive interval", name)
+				}
+			}()
+			create()
+		}()
+	}
+}
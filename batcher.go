@@ -0,0 +1,151 @@
+package gocollections
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+/*
+Batcher
+*/
+
+// Batcher accumulates items and emits them in batches when the batch reaches maximum size or the oldest item waits for
+// maximum wait duration. Batches are received from Batches channel. Add blocks while the batcher emits a batch which
+// is not received, so slow consumer slows down producers. Implementation of the batcher is running goroutine. To stop
+// this goroutine call Close() method when the batcher is not needed any more.
+type Batcher[T any] interface {
+	Add(item T) error
+	Batches() <-chan []T
+	Flush(ctx context.Context) error
+	Close(ctx context.Context) error
+}
+
+type batcher[T any] struct {
+	closeOnce sync.Once
+	maxSize   int
+	maxWait   time.Duration
+	in        chan T
+	out       chan []T
+	flushChan chan chan struct{} // flush requests, the channel is closed when the batch is emitted
+	closeChan chan struct{}      // closed by Close
+	doneChan  chan struct{}      // closed when the goroutine ends
+}
+
+// NewBatcher creates new Batcher with maximum batch size and maximum wait duration. If maxWait is 0, batches are
+// emitted only when they are full or flushed. It runs goroutine for emitting batches.
+func NewBatcher[T any](maxSize int, maxWait time.Duration) Batcher[T] {
+	if maxSize < 1 {
+		maxSize = 1
+	}
+	b := &batcher[T]{
+		maxSize:   maxSize,
+		maxWait:   maxWait,
+		in:        make(chan T),
+		out:       make(chan []T),
+		flushChan: make(chan chan struct{}),
+		closeChan: make(chan struct{}),
+		doneChan:  make(chan struct{}),
+	}
+
+	go b.run()
+
+	return b
+}
+
+// Add adds item to the current batch. It returns ErrClosed if the batcher is closed.
+func (b *batcher[T]) Add(item T) error {
+	select {
+	case <-b.closeChan:
+		return ErrClosed
+	default:
+	}
+	select {
+	case b.in <- item:
+		return nil
+	case <-b.closeChan:
+		return ErrClosed
+	case <-b.doneChan:
+		return ErrClosed
+	}
+}
+
+// Batches returns channel with emitted batches. The channel is closed after Close.
+func (b *batcher[T]) Batches() <-chan []T {
+	return b.out
+}
+
+// Flush emits the current batch and waits until it's received. It returns context error if the context ends before.
+func (b *batcher[T]) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	select {
+	case b.flushChan <- done:
+	case <-b.doneChan:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close emits the remaining items, waits until they are received and closes Batches channel. It returns context error
+// if the context ends before.
+func (b *batcher[T]) Close(ctx context.Context) error {
+	b.closeOnce.Do(func() {
+		close(b.closeChan)
+	})
+
+	select {
+	case <-b.doneChan:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run method runs the goroutine for emitting batches.
+func (b *batcher[T]) run() {
+	defer close(b.doneChan)
+	defer close(b.out)
+
+	var batch []T
+	var timer *time.Timer
+	var timerChan <-chan time.Time
+	emit := func() {
+		if timer != nil {
+			timer.Stop()
+			timer, timerChan = nil, nil
+		}
+		if len(batch) > 0 {
+			b.out <- batch
+			batch = nil
+		}
+	}
+
+	for {
+		select {
+		case item := <-b.in:
+			batch = append(batch, item)
+			if len(batch) == 1 && b.maxWait > 0 {
+				timer = time.NewTimer(b.maxWait)
+				timerChan = timer.C
+			}
+			if len(batch) >= b.maxSize {
+				emit()
+			}
+		case <-timerChan:
+			emit()
+		case done := <-b.flushChan:
+			emit()
+			close(done)
+		case <-b.closeChan:
+			emit()
+			return
+		}
+	}
+}
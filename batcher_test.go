@@ -0,0 +1,72 @@
+package gocollections
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBatcher(t *testing.T) {
+	t.Parallel()
+
+	b := NewBatcher[int](3, 50*time.Millisecond)
+	ctx := context.Background()
+
+	go func() {
+		for i := 1; i <= 4; i++ {
+			_ = b.Add(i)
+		}
+	}()
+
+	// Full batch is emitted immediately.
+	if batch := <-b.Batches(); len(batch) != 3 {
+		t.Errorf("Expect batch of 3 items, but got %v", batch)
+	}
+	// Rest is emitted after max wait.
+	select {
+	case batch := <-b.Batches():
+		if len(batch) != 1 || batch[0] != 4 {
+			t.Errorf("Expect batch [4], but got %v", batch)
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("No batch in timeout")
+	}
+
+	_ = b.Add(5)
+	go func() {
+		_ = b.Close(ctx)
+	}()
+	if batch := <-b.Batches(); len(batch) != 1 || batch[0] != 5 {
+		t.Errorf("Expect batch [5] on close, but got %v", batch)
+	}
+	if _, ok := <-b.Batches(); ok {
+		t.Error("Expect closed channel after Close")
+	}
+	if err := b.Add(6); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expect ErrClosed but got %v", err)
+	}
+}
+
+func TestBatcher_CloseWithBlockedAdd(t *testing.T) {
+	t.Parallel()
+
+	b := NewBatcher[int](1, 0)
+	_ = b.Add(1) // batch is emitted, but not received
+
+	errChan := make(chan error)
+	go func() {
+		errChan <- b.Add(2)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := b.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expect context.DeadlineExceeded, but got %v", err)
+	}
+	if err := <-errChan; !errors.Is(err, ErrClosed) {
+		t.Errorf("Expect ErrClosed from blocked Add, but got %v", err)
+	}
+	for range b.Batches() {
+	}
+}
@@ -0,0 +1,100 @@
+package gocollections
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// coarseClock caches current time with the configured resolution, so hot paths don't call time.Now on every
+// operation. Times of the clock keep monotonic clock reading, unless wall clock is configured. Nil clock returns exact
+// time with monotonic clock reading.
+type coarseClock struct {
+	resolution time.Duration
+	wallClock  bool
+	start      time.Time    // time of the clock creation with monotonic clock reading
+	now        atomic.Int64 // cached time in unix nanoseconds for wall clock, otherwise nanoseconds since start
+	quitChan   chan struct{}
+}
+
+// newCoarseClock returns clock if Config.TimeResolution or Config.WallClock is set, otherwise nil. The clock with
+// resolution runs goroutine updating cached time.
+func newCoarseClock(config Config) *coarseClock {
+	if config.TimeResolution <= 0 && !config.WallClock {
+		return nil
+	}
+	c := &coarseClock{
+		resolution: config.TimeResolution,
+		wallClock:  config.WallClock,
+		start:      time.Now(),
+	}
+	if c.resolution <= 0 {
+		return c
+	}
+	c.quitChan = make(chan struct{})
+	c.store(c.start)
+
+	activeCleaners.Add(1)
+	go func() {
+		defer activeCleaners.Add(-1)
+		config.labelGoroutine()
+		ticker := time.NewTicker(c.resolution)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case t := <-ticker.C:
+				c.store(t)
+			case <-c.quitChan:
+				return
+			}
+		}
+	}()
+	return c
+}
+
+// store caches the time.
+func (c *coarseClock) store(t time.Time) {
+	if c.wallClock {
+		c.now.Store(t.UnixNano())
+	} else {
+		c.now.Store(int64(t.Sub(c.start)))
+	}
+}
+
+// Now returns cached time, or exact time if the clock has no resolution or it's nil. Wall clock returns time without
+// monotonic clock reading, so it's compared by calendar time.
+func (c *coarseClock) Now() time.Time {
+	switch {
+	case c == nil:
+		return time.Now()
+	case c.resolution <= 0:
+		return time.Now().Round(0)
+	case c.wallClock:
+		return time.Unix(0, c.now.Load())
+	default:
+		return c.start.Add(time.Duration(c.now.Load()))
+	}
+}
+
+// expiry returns expiration time after duration from now. It's rounded up to the resolution of the clock.
+func (c *coarseClock) expiry(duration time.Duration) time.Time {
+	if duration == NoExpiry {
+		return noExpiryTime
+	}
+	t := c.Now().Add(duration)
+	if c == nil || c.resolution <= 0 {
+		return t
+	}
+	// Truncate drops monotonic clock reading, so the time is moved by the difference instead.
+	if r := t.Truncate(c.resolution); r.Before(t) {
+		return t.Add(c.resolution - t.Sub(r))
+	}
+	return t
+}
+
+// stop ends goroutine updating the clock.
+func (c *coarseClock) stop() {
+	if c != nil && c.quitChan != nil {
+		close(c.quitChan)
+	}
+}
@@ -0,0 +1,62 @@
+package gocollections
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeExpiredMap_TimeResolution(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](50*time.Millisecond, Config{TimeResolution: 100 * time.Millisecond})
+	defer tmap.Discard()
+
+	tmap.Add("key1", "value1")
+	if !tmap.Contains("key1") {
+		t.Error("Expect key1 in the map")
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	if tmap.Contains("key1") {
+		t.Error("Expect key1 expired")
+	}
+}
+
+func TestCoarseClock_Expiry(t *testing.T) {
+	t.Parallel()
+
+	c := newCoarseClock(Config{TimeResolution: 100 * time.Millisecond})
+	defer c.stop()
+
+	expiredAt := c.expiry(50 * time.Millisecond)
+	if !expiredAt.Equal(expiredAt.Truncate(100 * time.Millisecond)) {
+		t.Errorf("Expect expiration rounded to 100ms, but got %v", expiredAt)
+	}
+}
+
+func TestCoarseClock_Nil(t *testing.T) {
+	t.Parallel()
+
+	var c *coarseClock
+	if d := time.Since(c.Now()); d < 0 || d > time.Second {
+		t.Errorf("Expect nil clock returns current time, but it's %v off", d)
+	}
+	c.stop()
+}
+
+func TestCoarseClock_Monotonic(t *testing.T) {
+	t.Parallel()
+
+	c := newCoarseClock(Config{TimeResolution: 100 * time.Millisecond})
+	defer c.stop()
+	if s := c.expiry(time.Second).String(); !strings.Contains(s, "m=") {
+		t.Errorf("Expect expiration with monotonic clock reading, but got %s", s)
+	}
+
+	w := newCoarseClock(Config{WallClock: true})
+	defer w.stop()
+	if s := w.expiry(time.Second).String(); strings.Contains(s, "m=") {
+		t.Errorf("Expect wall clock expiration without monotonic clock reading, but got %s", s)
+	}
+}
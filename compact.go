@@ -0,0 +1,51 @@
+package gocollections
+
+/*
+Storage compaction
+*/
+
+// shrinkFactor returns Config.ShrinkFactor with default value. It returns 0 if shrinking is disabled.
+func (c Config) shrinkFactor() int {
+	if c.ShrinkFactor == 0 {
+		return 4
+	}
+	if c.ShrinkFactor < 0 {
+		return 0
+	}
+	return c.ShrinkFactor
+}
+
+// compact clears elements of data between its length and oldLen, so removed values can be collected, and reallocates
+// data if its capacity is factor times bigger than its length.
+func compact[V any](data []expiredElement[V], oldLen int, factor int) []expiredElement[V] {
+	tail := data[len(data):oldLen]
+	for i := range tail {
+		tail[i] = expiredElement[V]{}
+	}
+	if factor > 0 && cap(data) > factor*len(data) {
+		return append([]expiredElement[V](nil), data...)
+	}
+	return data
+}
+
+// rebuild copies data to new map if factor times more elements were removed since the last rebuild than the map has.
+// Go map doesn't release memory of removed elements, so the rebuild is the only way to shrink it. Keys for sampling are
+// reallocated like the list storage. It must be called with locked mutex.
+func (m *timeExpiredMap[K, V]) rebuild() {
+	factor := m.config.shrinkFactor()
+	if factor == 0 {
+		return
+	}
+	if cap(m.keys) > factor*len(m.keys) {
+		m.keys = append([]K(nil), m.keys...)
+	}
+	if m.removed <= factor*len(m.data) {
+		return
+	}
+	data := make(map[K]expiredElement[V], len(m.data))
+	for key, e := range m.data {
+		data[key] = e
+	}
+	m.data = data
+	m.removed = 0
+}
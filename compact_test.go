@@ -0,0 +1,109 @@
+package gocollections
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCompact(t *testing.T) {
+	t.Parallel()
+
+	data := make([]expiredElement[*int], 10)
+	for i := range data {
+		v := i
+		data[i].data = &v
+	}
+
+	kept := compact(data[:3], len(data), 0)
+	if cap(kept) != 10 {
+		t.Errorf("storage should be reused, cap: %d", cap(kept))
+	}
+	for i, e := range data[3:] {
+		if e.data != nil {
+			t.Errorf("removed element %d should be cleared", i+3)
+		}
+	}
+
+	if kept = compact(data[:2], 2, 4); cap(kept) != 2 || *kept[1].data != 1 {
+		t.Errorf("storage should be reallocated, cap: %d", cap(kept))
+	}
+}
+
+func TestTimeExpiredMap_Rebuild(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[int, int](time.Minute, Config{ShrinkFactor: 2})
+	defer tmap.Discard()
+	for i := 0; i < 10; i++ {
+		tmap.Add(i, i)
+	}
+	for i := 0; i < 7; i++ {
+		_ = tmap.Del(i)
+	}
+
+	m := tmap.(*timeExpiredMap[int, int])
+	m.removeExpired()
+	m.mu.Lock()
+	removed := m.removed
+	m.mu.Unlock()
+	if removed != 0 {
+		t.Errorf("map should be rebuilt, removed: %d", removed)
+	}
+	if size := tmap.Size(); size != 3 {
+		t.Errorf("want: %d, got: %d", 3, size)
+	}
+	if v, err := tmap.Get(9); err != nil || v != 9 {
+		t.Errorf("want: %d, got: %d, %v", 9, v, err)
+	}
+}
+
+func TestTimeExpiredList_EvictOldestClearsStorage(t *testing.T) {
+	t.Parallel()
+
+	tlist := NewTimeExpiredList[*int](time.Minute, Config{MaxLen: 2, ShrinkFactor: -1})
+	defer tlist.Discard()
+	for i := 0; i < 3; i++ {
+		v := i
+		tlist.Add(&v)
+	}
+
+	l := tlist.(*timeExpiredList[*int])
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.data) != 2 || *l.data[0].data != 1 || *l.data[1].data != 2 {
+		t.Errorf("oldest element should be evicted, got %d elements", len(l.data))
+	}
+	if tail := l.data[len(l.data):cap(l.data)]; len(tail) > 0 && tail[0].data != nil {
+		t.Errorf("evicted element should be cleared")
+	}
+}
+
+func TestTimeExpiredList_DelClearsStorage(t *testing.T) {
+	t.Parallel()
+
+	tlist := NewTimeExpiredList[*int](time.Minute)
+	defer tlist.Discard()
+	var handle Handle
+	for i := 0; i < 4; i++ {
+		v := i
+		handle = tlist.AddWithHandle(&v)
+	}
+	if err := tlist.Del(0); err != nil {
+		t.Fatal(err)
+	}
+	if err := tlist.DelByHandle(handle); err != nil {
+		t.Fatal(err)
+	}
+
+	l := tlist.(*timeExpiredList[*int])
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.data) != 2 || *l.data[0].data != 1 || *l.data[1].data != 2 {
+		t.Errorf("deleted elements should be removed, got %d elements", len(l.data))
+	}
+	for _, e := range l.data[len(l.data):cap(l.data)] {
+		if e.data != nil {
+			t.Errorf("deleted element should be cleared")
+		}
+	}
+}
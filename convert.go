@@ -0,0 +1,80 @@
+package gocollections
+
+import "time"
+
+/*
+Conversions between collections
+*/
+
+// ToSet returns set of unexpired values of the list.
+func ToSet[V comparable](l TimeExpiredList[V]) map[V]struct{} {
+	result := make(map[V]struct{})
+	for _, v := range l.GetAll() {
+		result[v] = struct{}{}
+	}
+	return result
+}
+
+// ToMap creates new TimeExpiredMap from unexpired values of the list. Key of the element is returned by keyFn.
+// Elements keep remaining duration from the list.
+func ToMap[K comparable, V any](l TimeExpiredList[V], keyFn func(V) K, duration time.Duration,
+	configs ...Config) TimeExpiredMap[K, V] {
+	var entries []Entry[K, V]
+	for _, e := range l.GetAllEntries() {
+		if ttl := e.TTL(); ttl > 0 {
+			entries = append(entries, Entry[K, V]{Key: keyFn(e.Value), Value: e.Value, TTL: ttl})
+		}
+	}
+	tmap := NewTimeExpiredMap[K, V](duration, configs...)
+	tmap.AddAll(entries)
+	return tmap
+}
+
+// ToSlice returns unexpired values of the map.
+func ToSlice[K comparable, V any](m TimeExpiredMap[K, V]) []V {
+	var result []V
+	for _, e := range m.GetAllEntries() {
+		result = append(result, e.Value)
+	}
+	return result
+}
+
+// FromSlice creates new TimeExpiredList with values.
+func FromSlice[V any](values []V, duration time.Duration, configs ...Config) TimeExpiredList[V] {
+	tlist := NewTimeExpiredList[V](duration, configs...)
+	for _, v := range values {
+		tlist.Add(v)
+	}
+	return tlist
+}
+
+// MapFromSlice creates new TimeExpiredMap with values. Key of the element is returned by keyFn.
+func MapFromSlice[K comparable, V any](values []V, keyFn func(V) K, duration time.Duration,
+	configs ...Config) TimeExpiredMap[K, V] {
+	entries := make([]Entry[K, V], 0, len(values))
+	for _, v := range values {
+		entries = append(entries, Entry[K, V]{Key: keyFn(v), Value: v})
+	}
+	tmap := NewTimeExpiredMap[K, V](duration, configs...)
+	tmap.AddAll(entries)
+	return tmap
+}
+
+// Migrate copies unexpired elements of the map src to the map dst. Function transform gets key, value and remaining
+// duration of every element and returns new key, value and duration. If returned duration is 0, then default duration
+// of dst is used. It returns number of migrated elements. Elements are not removed from src.
+func Migrate[K comparable, V any, K2 comparable, V2 any](src TimeExpiredMap[K, V], dst TimeExpiredMap[K2, V2],
+	transform func(key K, value V, ttl time.Duration) (K2, V2, time.Duration)) int {
+	var entries []Entry[K2, V2]
+	for _, e := range src.GetAllEntries() {
+		key, value, ttl := transform(e.Key, e.Value, e.TTL)
+		entries = append(entries, Entry[K2, V2]{Key: key, Value: value, TTL: ttl})
+	}
+	count := len(entries)
+	for _, err := range dst.AddAll(entries) {
+		if err != nil {
+			count--
+		}
+	}
+	return count
+}
@@ -0,0 +1,76 @@
+package gocollections
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConversions(t *testing.T) {
+	t.Parallel()
+
+	tlist := FromSlice([]string{"a-1", "b-2", "a-1"}, 10*time.Second)
+	defer tlist.Discard()
+
+	set := ToSet(tlist)
+	if len(set) != 2 {
+		t.Errorf("Expect 2 values in set, but got %d", len(set))
+	}
+
+	tmap := ToMap(tlist, func(v string) string {
+		return strings.Split(v, "-")[0]
+	}, time.Minute)
+	defer tmap.Discard()
+
+	if tmap.Size() != 2 {
+		t.Errorf("want: %d, got: %d", 2, tmap.Size())
+	}
+	for _, e := range tmap.GetAllEntries() {
+		if e.TTL > 10*time.Second {
+			t.Errorf("Expect TTL from the list, but got %s", e.TTL)
+		}
+	}
+
+	values := ToSlice(tmap)
+	sort.Strings(values)
+	if len(values) != 2 || values[0] != "a-1" || values[1] != "b-2" {
+		t.Errorf("Expect values [a-1 b-2], but got %v", values)
+	}
+
+	tmap2 := MapFromSlice([]string{"a-1", "b-2"}, func(v string) string {
+		return v[2:]
+	}, time.Minute)
+	defer tmap2.Discard()
+
+	if got, _ := tmap2.Get("2"); got != "b-2" {
+		t.Errorf("want: %s, got: %s", "b-2", got)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+
+	src := NewTimeExpiredMap[string, int](10 * time.Second)
+	defer src.Discard()
+	dst := NewTimeExpiredMap[int, string](time.Minute)
+	defer dst.Discard()
+
+	src.Add("a", 1)
+	src.Add("b", 2)
+
+	count := Migrate(src, dst, func(key string, value int, ttl time.Duration) (int, string, time.Duration) {
+		return value, key, ttl
+	})
+	if count != 2 {
+		t.Errorf("Expect 2 migrated elements, but got %d", count)
+	}
+	if got, _ := dst.Get(2); got != "b" {
+		t.Errorf("want: %s, got: %s", "b", got)
+	}
+	for _, e := range dst.GetAllEntries() {
+		if e.TTL > 10*time.Second {
+			t.Errorf("Expect remaining duration preserved, but got %v", e.TTL)
+		}
+	}
+}
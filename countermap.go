@@ -0,0 +1,191 @@
+package gocollections
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+/*
+Counter Map
+*/
+
+// CounterMap counts events per key in a time window. Counter of the key is created by the first Incr and expires after
+// TTL, so counts are reset by expiration. Implementation of this map is running goroutine which removes expired
+// counters. To stop this goroutine call Discard() method when this map is not needed any more.
+type CounterMap[K comparable] interface {
+	// Incr adds delta to the counter of the key, which is created if it's not in the map. It returns new count.
+	Incr(key K, delta int64) int64
+	// Get returns count of the key. It's 0 if the key is not in the map.
+	Get(key K) int64
+	Del(key K)
+	// Sum returns total count of all keys.
+	Sum() int64
+	// Top returns n keys with the highest counts, sorted by count descending. It returns nil if n is not positive.
+	Top(n int) []CounterEntry[K]
+	Size() int
+	Discard()
+}
+
+// CounterEntry is a key with its count.
+type CounterEntry[K comparable] struct {
+	Key   K
+	Count int64
+}
+
+// CounterTTL defines when the counter of CounterMap expires.
+type CounterTTL int
+
+const (
+	CounterFixedTTL   CounterTTL = iota // Counter expires TTL after it was created, ex. for fixed rate limit windows.
+	CounterSlidingTTL                   // Every Incr extends the counter by TTL, so it expires TTL after the last event.
+)
+
+type counter struct {
+	count     int64
+	expiredAt time.Time
+}
+
+type counterMap[K comparable] struct {
+	config   Config
+	mu       sync.Mutex
+	ttl      time.Duration
+	mode     CounterTTL
+	data     map[K]*counter
+	quitChan chan struct{}
+	clock    *coarseClock    // cached clock if Config.TimeResolution is set
+	suspend  suspendDetector // detects suspend of the process between cleaning runs
+	closed   bool
+}
+
+// NewCounterMap creates new CounterMap with TTL of counters and mode of their expiration.
+func NewCounterMap[K comparable](ttl time.Duration, mode CounterTTL, configs ...Config) CounterMap[K] {
+	config := newConfig(configs)
+	m := &counterMap[K]{
+		config:   config,
+		ttl:      ttl,
+		mode:     mode,
+		data:     make(map[K]*counter),
+		quitChan: make(chan struct{}),
+		clock:    newCoarseClock(config),
+	}
+
+	startCleaner(config, m.quitChan, m.removeExpired)
+
+	return m
+}
+
+func (m *counterMap[K]) Incr(key K, delta int64) int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return 0
+	}
+	c, found := m.data[key]
+	if !found || !c.expiredAt.After(m.clock.Now()) {
+		c = &counter{expiredAt: m.clock.expiry(m.ttl)}
+		m.data[key] = c
+	} else if m.mode == CounterSlidingTTL {
+		c.expiredAt = m.clock.expiry(m.ttl)
+	}
+	c.count += delta
+	return c.count
+}
+
+func (m *counterMap[K]) Get(key K) int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if c, found := m.data[key]; found && c.expiredAt.After(m.clock.Now()) {
+		return c.count
+	}
+	return 0
+}
+
+func (m *counterMap[K]) Del(key K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.data, key)
+}
+
+func (m *counterMap[K]) Sum() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.clock.Now()
+	var sum int64
+	for _, c := range m.data {
+		if c.expiredAt.After(now) {
+			sum += c.count
+		}
+	}
+	return sum
+}
+
+func (m *counterMap[K]) Top(n int) []CounterEntry[K] {
+	if n <= 0 {
+		return nil
+	}
+	m.mu.Lock()
+	now := m.clock.Now()
+	var result []CounterEntry[K]
+	for key, c := range m.data {
+		if c.expiredAt.After(now) {
+			result = append(result, CounterEntry[K]{Key: key, Count: c.count})
+		}
+	}
+	m.mu.Unlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Count > result[j].Count
+	})
+	if len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
+
+// Size returns number of unexpired counters.
+func (m *counterMap[K]) Size() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.clock.Now()
+	size := 0
+	for _, c := range m.data {
+		if c.expiredAt.After(now) {
+			size++
+		}
+	}
+	return size
+}
+
+// Discard method stops the goroutine for removing expired counters and discards data.
+func (m *counterMap[K]) Discard() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return
+	}
+	m.closed = true
+	close(m.quitChan)
+	m.clock.stop()
+	m.data = nil
+}
+
+// removeExpired removes expired counters.
+func (m *counterMap[K]) removeExpired() {
+	m.suspend.handle(m.config, &m.mu, m.shiftExpirations)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.clock.Now()
+	for key, c := range m.data {
+		if !c.expiredAt.After(now) {
+			delete(m.data, key)
+		}
+	}
+}
+
+// shiftExpirations moves expirations of all counters by d. It must be called with locked mutex.
+func (m *counterMap[K]) shiftExpirations(d time.Duration) {
+	for _, c := range m.data {
+		c.expiredAt = shiftExpiry(c.expiredAt, d)
+	}
+}
@@ -0,0 +1,75 @@
+package gocollections
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCounterMap(t *testing.T) {
+	t.Parallel()
+
+	cmap := NewCounterMap[string](time.Minute, CounterFixedTTL)
+	defer cmap.Discard()
+	cmap.Incr("a", 1)
+	cmap.Incr("b", 5)
+	cmap.Incr("c", 2)
+	if got := cmap.Incr("a", 2); got != 3 {
+		t.Errorf("want: %d, got: %d", 3, got)
+	}
+
+	if sum := cmap.Sum(); sum != 10 {
+		t.Errorf("want: %d, got: %d", 10, sum)
+	}
+	top := cmap.Top(2)
+	if len(top) != 2 || top[0] != (CounterEntry[string]{Key: "b", Count: 5}) || top[1].Key != "a" {
+		t.Errorf("unexpected top: %v", top)
+	}
+	if top := cmap.Top(-1); top != nil {
+		t.Errorf("Expect no top keys, but got %v", top)
+	}
+	cmap.Del("b")
+	if got := cmap.Get("b"); got != 0 {
+		t.Errorf("want: %d, got: %d", 0, got)
+	}
+}
+
+func TestCounterMap_TTL(t *testing.T) {
+	t.Parallel()
+
+	fixed := NewCounterMap[string](50*time.Millisecond, CounterFixedTTL)
+	defer fixed.Discard()
+	sliding := NewCounterMap[string](50*time.Millisecond, CounterSlidingTTL)
+	defer sliding.Discard()
+
+	for i := 0; i < 3; i++ {
+		fixed.Incr("key", 1)
+		sliding.Incr("key", 1)
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if got := fixed.Get("key"); got != 0 {
+		t.Errorf("fixed counter should expire, got: %d", got)
+	}
+	if got := sliding.Get("key"); got != 3 {
+		t.Errorf("sliding want: %d, got: %d", 3, got)
+	}
+}
+
+func TestCounterMap_Suspend(t *testing.T) {
+	t.Parallel()
+
+	cmap := NewCounterMap[string](10*time.Second, CounterFixedTTL, Config{SuspendPolicy: SuspendExpire})
+	defer cmap.Discard()
+	cmap.Incr("a", 1)
+
+	// Simulate expiring of counters lapsed during a minute of suspend.
+	m := cmap.(*counterMap[string])
+	m.mu.Lock()
+	m.shiftExpirations(m.config.suspendShift(time.Minute))
+	m.mu.Unlock()
+	m.removeExpired()
+
+	if got := cmap.Get("a"); got != 0 {
+		t.Errorf("want: %d, got: %d", 0, got)
+	}
+}
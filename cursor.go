@@ -0,0 +1,45 @@
+package gocollections
+
+/*
+Map cursor
+*/
+
+// MapCursor iterates over elements of the map in batches. It holds the lock of the map only while keys are collected
+// at creation and while each element is read, so a long export doesn't block writers. Elements removed after creation
+// of the cursor are skipped and elements added after it are not returned.
+type MapCursor[K comparable, V any] struct {
+	m    MapReader[K, V]
+	keys []K
+}
+
+// NewMapCursor creates cursor over unexpired elements of the map.
+func NewMapCursor[K comparable, V any](m MapReader[K, V]) *MapCursor[K, V] {
+	c := &MapCursor[K, V]{m: m}
+	m.Range(func(key K, value V) bool {
+		c.keys = append(c.keys, key)
+		return true
+	})
+	return c
+}
+
+// Next returns next batch of at most batchSize unexpired elements. It returns empty slice when all elements were
+// returned.
+func (c *MapCursor[K, V]) Next(batchSize int) []Entry[K, V] {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	var result []Entry[K, V]
+	for len(result) < batchSize && len(c.keys) > 0 {
+		key := c.keys[0]
+		c.keys = c.keys[1:]
+		if e, err := c.m.GetEntry(key); err == nil {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// Remaining returns number of keys which were not returned yet.
+func (c *MapCursor[K, V]) Remaining() int {
+	return len(c.keys)
+}
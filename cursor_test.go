@@ -0,0 +1,33 @@
+package gocollections
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMapCursor(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[int, int](10 * time.Second)
+	defer tmap.Discard()
+	for i := 0; i < 5; i++ {
+		tmap.Add(i, i)
+	}
+
+	c := tmap.Cursor()
+	_ = tmap.Del(4)
+	tmap.Add(5, 5)
+
+	seen := make(map[int]bool)
+	for batch := c.Next(2); len(batch) > 0; batch = c.Next(2) {
+		if len(batch) > 2 {
+			t.Errorf("Expect batch of at most 2 elements, but got %d", len(batch))
+		}
+		for _, e := range batch {
+			seen[e.Key] = true
+		}
+	}
+	if len(seen) != 4 || seen[4] || seen[5] {
+		t.Errorf("Expect keys 0-3, but got %v", seen)
+	}
+}
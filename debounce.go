@@ -0,0 +1,120 @@
+package gocollections
+
+import (
+	"sync"
+	"time"
+)
+
+/*
+Debounce and Throttle Maps
+*/
+
+// DebounceMap coalesces bursts of calls per key into one execution. Function of the last call is executed when there
+// is no call of the key for quiet duration. Keys are removed after the execution, so idle keys don't stay in the map.
+type DebounceMap[K comparable] interface {
+	Call(key K, fn func())
+	Pending() int
+	Discard()
+}
+
+type debounceMap[K comparable] struct {
+	mu     sync.Mutex
+	quiet  time.Duration
+	timers map[K]*debounceTimer
+	closed bool // true after Discard
+}
+
+type debounceTimer struct {
+	timer *time.Timer
+	fn    func()
+}
+
+// NewDebounceMap creates new DebounceMap with quiet duration.
+func NewDebounceMap[K comparable](quiet time.Duration) DebounceMap[K] {
+	return &debounceMap[K]{
+		quiet:  quiet,
+		timers: make(map[K]*debounceTimer),
+	}
+}
+
+// Call schedules fn to be executed after quiet duration. If the key is called again before, the previous fn is
+// discarded and quiet duration starts again.
+func (m *debounceMap[K]) Call(key K, fn func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return
+	}
+	if t, found := m.timers[key]; found {
+		t.timer.Stop()
+	}
+	t := &debounceTimer{fn: fn}
+	t.timer = time.AfterFunc(m.quiet, func() {
+		m.mu.Lock()
+		if m.timers[key] != t {
+			// Timer was replaced by next call.
+			m.mu.Unlock()
+			return
+		}
+		delete(m.timers, key)
+		m.mu.Unlock()
+		t.fn()
+	})
+	m.timers[key] = t
+}
+
+// Pending returns number of keys waiting for execution.
+func (m *debounceMap[K]) Pending() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.timers)
+}
+
+// Discard method cancels all pending executions.
+func (m *debounceMap[K]) Discard() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	for key, t := range m.timers {
+		t.timer.Stop()
+		delete(m.timers, key)
+	}
+}
+
+// ThrottleMap executes function of the key at most once per interval. Calls within the interval are dropped. It's
+// built on TimeExpiredMap, so keys idle for the interval are removed by running goroutine. To stop this goroutine call
+// Discard() method when this map is not needed any more.
+type ThrottleMap[K comparable] interface {
+	Call(key K, fn func()) bool
+	Discard()
+}
+
+type throttleMap[K comparable] struct {
+	mu    sync.Mutex
+	calls TimeExpiredMap[K, struct{}] // keys executed within the interval
+}
+
+// NewThrottleMap creates new ThrottleMap with interval.
+func NewThrottleMap[K comparable](interval time.Duration, configs ...Config) ThrottleMap[K] {
+	return &throttleMap[K]{
+		calls: NewTimeExpiredMap[K, struct{}](interval, configs...),
+	}
+}
+
+// Call executes fn if the key was not executed within the interval and returns true. Otherwise, it returns false.
+func (m *throttleMap[K]) Call(key K, fn func()) bool {
+	m.mu.Lock()
+	if m.calls.Contains(key) {
+		m.mu.Unlock()
+		return false
+	}
+	m.calls.Add(key, struct{}{})
+	m.mu.Unlock()
+	fn()
+	return true
+}
+
+// Discard method stops the goroutine for removing idle keys.
+func (m *throttleMap[K]) Discard() {
+	m.calls.Discard()
+}
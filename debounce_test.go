@@ -0,0 +1,51 @@
+package gocollections
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDebounceMap(t *testing.T) {
+	t.Parallel()
+
+	dm := NewDebounceMap[string](50 * time.Millisecond)
+	defer dm.Discard()
+
+	var calls, last atomic.Int32
+	for i := 1; i <= 5; i++ {
+		i := int32(i)
+		dm.Call("key", func() {
+			calls.Add(1)
+			last.Store(i)
+		})
+	}
+	time.Sleep(150 * time.Millisecond)
+
+	if calls.Load() != 1 || last.Load() != 5 {
+		t.Errorf("Expect one call of the last function, but got %d calls, last %d", calls.Load(), last.Load())
+	}
+	if dm.Pending() != 0 {
+		t.Errorf("want: %d, got: %d", 0, dm.Pending())
+	}
+}
+
+func TestThrottleMap(t *testing.T) {
+	t.Parallel()
+
+	tm := NewThrottleMap[string](50 * time.Millisecond)
+	defer tm.Discard()
+
+	var calls int
+	for i := 0; i < 5; i++ {
+		tm.Call("key", func() { calls++ })
+	}
+	if calls != 1 {
+		t.Errorf("want: %d, got: %d", 1, calls)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if !tm.Call("key", func() { calls++ }) || calls != 2 {
+		t.Errorf("Expect call after interval, but got %d calls", calls)
+	}
+}
@@ -0,0 +1,50 @@
+package gocollections
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+/*
+Content digests
+*/
+
+// ContentHash returns hash of unexpired elements of the map. It doesn't depend on order of elements or their
+// expiration, so two maps with the same keys and values have the same hash. Keys and values are hashed by their fmt
+// representation.
+func ContentHash[K comparable, V any](m TimeExpiredMap[K, V]) uint64 {
+	var hash uint64
+	m.Range(func(key K, value V) bool {
+		hash ^= hashElement(key, value)
+		return true
+	})
+	return hash
+}
+
+// Digest returns content hashes of unexpired elements split to buckets by hash of the key. Two maps can compare their
+// digests and sync only diverging buckets.
+func Digest[K comparable, V any](m TimeExpiredMap[K, V], buckets int) []uint64 {
+	if buckets < 1 {
+		buckets = 1
+	}
+	result := make([]uint64, buckets)
+	m.Range(func(key K, value V) bool {
+		result[KeyBucket(key, buckets)] ^= hashElement(key, value)
+		return true
+	})
+	return result
+}
+
+// KeyBucket returns bucket of the key used by Digest.
+func KeyBucket[K comparable](key K, buckets int) int {
+	h := fnv.New64a()
+	_, _ = fmt.Fprint(h, key)
+	return int(h.Sum64() % uint64(buckets))
+}
+
+// hashElement returns hash of the key and value.
+func hashElement(key, value any) uint64 {
+	h := fnv.New64a()
+	_, _ = fmt.Fprintf(h, "%v\x00%v", key, value)
+	return h.Sum64()
+}
@@ -0,0 +1,36 @@
+package gocollections
+
+import (
+	"testing"
+	"time"
+)
+
+func TestContentHash(t *testing.T) {
+	t.Parallel()
+
+	a := NewTimeExpiredMap[string, int](10 * time.Second)
+	defer a.Discard()
+	b := NewTimeExpiredMap[string, int](time.Minute)
+	defer b.Discard()
+
+	a.Add("key1", 1)
+	a.Add("key2", 2)
+	b.Add("key2", 2)
+	b.Add("key1", 1)
+
+	if ContentHash(a) != ContentHash(b) {
+		t.Error("Expect the same hash of the same content")
+	}
+
+	b.Add("key2", 3)
+	if ContentHash(a) == ContentHash(b) {
+		t.Error("Expect different hash of different content")
+	}
+	da, db := Digest(a, 4), Digest(b, 4)
+	for i := range da {
+		diverged := i == KeyBucket("key2", 4)
+		if (da[i] != db[i]) != diverged {
+			t.Errorf("bucket %d: expect diverged %v, digests %d and %d", i, diverged, da[i], db[i])
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package gocollections
+
+/*
+Equality and diff of collections
+*/
+
+// EqualLists returns true if both lists have the same unexpired values in the same order. Values are compared by eq.
+func EqualLists[V any](a, b TimeExpiredList[V], eq func(V, V) bool) bool {
+	av, bv := a.GetAll(), b.GetAll()
+	if len(av) != len(bv) {
+		return false
+	}
+	for i := range av {
+		if !eq(av[i], bv[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// EqualMaps returns true if both maps have the same unexpired keys with equal values. Values are compared by eq.
+func EqualMaps[K comparable, V any](a, b TimeExpiredMap[K, V], eq func(V, V) bool) bool {
+	d := Diff(a, b, eq)
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// EqualSets returns true if both sets have the same values.
+func EqualSets[V comparable](a, b map[V]struct{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for v := range a {
+		if _, found := b[v]; !found {
+			return false
+		}
+	}
+	return true
+}
+
+// MapDiff is a difference between map before and after a change returned by Diff.
+type MapDiff[K comparable, V any] struct {
+	Added   map[K]V          // elements only in the map after
+	Removed map[K]V          // elements only in the map before
+	Changed map[K]Pair[V, V] // value before and after of elements with not equal values
+}
+
+// Diff returns difference between unexpired elements of map before and after a change. Values are compared by eq.
+func Diff[K comparable, V any](before, after TimeExpiredMap[K, V], eq func(V, V) bool) MapDiff[K, V] {
+	d := MapDiff[K, V]{
+		Added:   make(map[K]V),
+		Removed: make(map[K]V),
+		Changed: make(map[K]Pair[V, V]),
+	}
+	oldValues := make(map[K]V)
+	for _, e := range before.GetAllEntries() {
+		oldValues[e.Key] = e.Value
+	}
+	for _, e := range after.GetAllEntries() {
+		oldValue, found := oldValues[e.Key]
+		if !found {
+			d.Added[e.Key] = e.Value
+			continue
+		}
+		if !eq(oldValue, e.Value) {
+			d.Changed[e.Key] = Pair[V, V]{First: oldValue, Second: e.Value}
+		}
+		delete(oldValues, e.Key)
+	}
+	for key, v := range oldValues {
+		d.Removed[key] = v
+	}
+	return d
+}
@@ -0,0 +1,43 @@
+package gocollections
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	eq := func(a, b string) bool { return a == b }
+
+	old := MapFromSlice([]string{"1", "2", "3"}, func(v string) string { return v }, 10*time.Second)
+	defer old.Discard()
+	new := MapFromSlice([]string{"1", "2", "4"}, func(v string) string { return v }, 10*time.Second)
+	defer new.Discard()
+	new.Add("2", "changed")
+
+	d := Diff(old, new, eq)
+	if _, found := d.Added["4"]; !found || len(d.Added) != 1 {
+		t.Errorf("Expect added key 4, but got %v", d.Added)
+	}
+	if _, found := d.Removed["3"]; !found || len(d.Removed) != 1 {
+		t.Errorf("Expect removed key 3, but got %v", d.Removed)
+	}
+	if c, found := d.Changed["2"]; !found || c.First != "2" || c.Second != "changed" {
+		t.Errorf("Expect changed key 2, but got %v", d.Changed)
+	}
+	if EqualMaps(old, new, eq) || !EqualMaps(old, old, eq) {
+		t.Error("EqualMaps returns wrong result")
+	}
+
+	list1 := FromSlice([]string{"1", "2"}, 10*time.Second)
+	defer list1.Discard()
+	list2 := FromSlice([]string{"2", "1"}, 10*time.Second)
+	defer list2.Discard()
+	if EqualLists(list1, list2, eq) {
+		t.Error("Lists with different order should not be equal")
+	}
+	if !EqualSets(ToSet(list1), ToSet(list2)) {
+		t.Error("Sets with the same values should be equal")
+	}
+}
@@ -0,0 +1,74 @@
+package gocollections
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrKeyNotFound     = errors.New("key not found")
+	ErrIndexOutOfBound = errors.New("index out of bound")
+	ErrExpired         = errors.New("element expired")       // When an element is present in the collection but the validity time expires.
+	ErrTypeMismatch    = errors.New("type mismatch")         // When an element is not of the requested type.
+	ErrClosed          = errors.New("collection closed")     // When the collection is used after Discard.
+	ErrInvalidTTL      = errors.New("invalid ttl")           // When the element duration is not valid, ex. negative.
+	ErrRejected        = errors.New("element rejected")      // When the element is rejected by screening.
+	ErrNoNodes         = errors.New("no nodes")              // When the hash ring has no nodes.
+	ErrTruncated       = errors.New("offset truncated")      // When the log entry was already truncated.
+	ErrLeaseNotFound   = errors.New("lease not found")       // When the lease expired or was released.
+	ErrExpiringSoon    = errors.New("element expiring soon") // When the element expires sooner than requested.
+	ErrOwnerExists     = errors.New("owner exists")          // When the owner is already registered.
+	ErrOwnerNotFound   = errors.New("owner not found")       // When the owner was closed or missed its heartbeat.
+	ErrBusy            = errors.New("collection busy")       // When the lock is not acquired within Config.TryLockTimeout.
+	ErrDeleted         = errors.New("key deleted")           // When the key was recently deleted by SoftDel.
+	ErrComputePanicked = errors.New("compute panicked")      // When the shared computation of the key panicked.
+)
+
+// KeyError is returned when element with Key is not found. It matches ErrKeyNotFound via errors.Is.
+type KeyError struct {
+	Key any
+}
+
+func (e *KeyError) Error() string {
+	return fmt.Sprintf("%s: %v", ErrKeyNotFound, e.Key)
+}
+
+func (e *KeyError) Unwrap() error {
+	return ErrKeyNotFound
+}
+
+// IndexError is returned when Index is out of bound of collection with length Len. It matches ErrIndexOutOfBound via
+// errors.Is.
+type IndexError struct {
+	Index int
+	Len   int
+}
+
+func (e *IndexError) Error() string {
+	return fmt.Sprintf("%s: index %d, length %d", ErrIndexOutOfBound, e.Index, e.Len)
+}
+
+func (e *IndexError) Unwrap() error {
+	return ErrIndexOutOfBound
+}
+
+// ExpiredError is returned when element is present in the collection but it expired at ExpiredAt. Key is a key of the
+// element in map or index of the element in list. It matches ErrExpired via errors.Is. Expired element is treated as
+// missing, so it matches ErrKeyNotFound too.
+type ExpiredError struct {
+	Key       any
+	ExpiredAt time.Time
+}
+
+func (e *ExpiredError) Error() string {
+	return fmt.Sprintf("%s: %v at %s", ErrExpired, e.Key, e.ExpiredAt.Format(time.RFC3339))
+}
+
+func (e *ExpiredError) Unwrap() error {
+	return ErrExpired
+}
+
+func (e *ExpiredError) Is(target error) bool {
+	return target == ErrKeyNotFound
+}
@@ -0,0 +1,46 @@
+package gocollections
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestErrors(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](10 * time.Second)
+	defer tmap.Discard()
+
+	_, err := tmap.Get("missing")
+	if !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Expect ErrKeyNotFound but got %v", err)
+	}
+	var keyErr *KeyError
+	if !errors.As(err, &keyErr) || keyErr.Key != "missing" {
+		t.Errorf("Expect KeyError with key 'missing' but got %v", err)
+	}
+
+	tmap.AddWithDuration("expired", "test 1", -1*time.Second)
+	_, err = tmap.Get("expired")
+	var expiredErr *ExpiredError
+	if !errors.As(err, &expiredErr) || expiredErr.Key != "expired" || !errors.Is(err, ErrExpired) {
+		t.Errorf("Expect ExpiredError with key 'expired' but got %v", err)
+	}
+	if !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Expect expired key to match ErrKeyNotFound but got %v", err)
+	}
+
+	tlist := NewTimeExpiredList[string](10 * time.Second)
+	defer tlist.Discard()
+
+	tlist.Add("value1")
+	_, err = tlist.Get(3)
+	var indexErr *IndexError
+	if !errors.As(err, &indexErr) || indexErr.Index != 3 || indexErr.Len != 1 {
+		t.Errorf("Expect IndexError with index 3 and length 1 but got %v", err)
+	}
+	if !errors.Is(err, ErrIndexOutOfBound) {
+		t.Errorf("Expect ErrIndexOutOfBound but got %v", err)
+	}
+}
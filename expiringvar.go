@@ -0,0 +1,59 @@
+package gocollections
+
+import (
+	"errors"
+	"time"
+)
+
+/*
+Expiring Var
+*/
+
+// ExpiringVar holds one value which expires, ex. current config or the latest auth token. Watchers are notified when
+// the value is set or expires. It's built on TimeExpiredMap, so it's running goroutine which removes expired value. To
+// stop this goroutine call Discard() method when the var is not needed any more.
+type ExpiringVar[V any] interface {
+	// Set sets the value with default duration.
+	Set(value V)
+	SetWithDuration(value V, duration time.Duration)
+	// Get returns the value. It returns error matching ErrExpired if the value is not set or it expired.
+	Get() (V, error)
+	// Watch returns watch with events of the value. Key of the events is empty struct. The value expiration is sent
+	// when the cleaning goroutine removes it.
+	Watch(bufferSize int) Watch[struct{}, V]
+	Discard()
+}
+
+type expiringVar[V any] struct {
+	m TimeExpiredMap[struct{}, V]
+}
+
+// NewExpiringVar creates new ExpiringVar with default duration of the value.
+func NewExpiringVar[V any](duration time.Duration, configs ...Config) ExpiringVar[V] {
+	return &expiringVar[V]{m: NewTimeExpiredMap[struct{}, V](duration, configs...)}
+}
+
+func (v *expiringVar[V]) Set(value V) {
+	v.m.Add(struct{}{}, value)
+}
+
+func (v *expiringVar[V]) SetWithDuration(value V, duration time.Duration) {
+	v.m.AddWithDuration(struct{}{}, value, duration)
+}
+
+func (v *expiringVar[V]) Get() (V, error) {
+	value, err := v.m.Get(struct{}{})
+	if errors.Is(err, ErrKeyNotFound) {
+		return value, ErrExpired
+	}
+	return value, err
+}
+
+func (v *expiringVar[V]) Watch(bufferSize int) Watch[struct{}, V] {
+	return v.m.WatchWhere(func(key struct{}, value V) bool { return true }, bufferSize)
+}
+
+// Discard stops the goroutine for removing expired value and closes watches.
+func (v *expiringVar[V]) Discard() {
+	v.m.Discard()
+}
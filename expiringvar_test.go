@@ -0,0 +1,41 @@
+package gocollections
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExpiringVar(t *testing.T) {
+	t.Parallel()
+
+	v := NewExpiringVar[string](30*time.Millisecond, Config{CleanJobInterval: 10 * time.Millisecond})
+	defer v.Discard()
+	if _, err := v.Get(); !errors.Is(err, ErrExpired) {
+		t.Errorf("want: %v, got: %v", ErrExpired, err)
+	}
+
+	w := v.Watch(10)
+	v.Set("token1")
+	v.Set("token2")
+	if got, err := v.Get(); err != nil || got != "token2" {
+		t.Errorf("want: %s, got: %s, %v", "token2", got, err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, err := v.Get(); !errors.Is(err, ErrExpired) {
+		t.Errorf("want: %v, got: %v", ErrExpired, err)
+	}
+
+	want := []EventKind{EventAdded, EventUpdated, EventExpired}
+	for _, kind := range want {
+		select {
+		case e := <-w.C():
+			if e.Kind != kind {
+				t.Errorf("want: %d, got: %d", kind, e.Kind)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("event %d not received", kind)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package gocollections
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+/*
+State Machine Map
+*/
+
+// State is a state of the key in FSMMap.
+type State string
+
+// Timeout is a transition of FSMMap which happens when the key stays in the state longer than After. If Next is empty
+// state, then the key is removed from the map.
+type Timeout struct {
+	After time.Duration
+	Next  State
+}
+
+// StateEvent is sent to FSMMap events channel when the key changes state because of timeout.
+type StateEvent[K comparable] struct {
+	Key  K
+	From State
+	To   State
+	At   time.Time
+}
+
+// FSMMap is a map of keys with states. When the key stays in the state longer than configured timeout, it
+// automatically transitions to the next state. Passed timeouts are applied when the key is read, so reads are not stale
+// between checks. Implementation of this map is running goroutine which checks timeouts and sends events of keys which
+// are not read. To stop this goroutine call Discard() method when this map is not needed any more.
+type FSMMap[K comparable] interface {
+	Set(key K, state State)
+	Get(key K) (State, error)
+	Del(key K) error
+	Size() int
+	Events() chan StateEvent[K]
+	Discard()
+}
+
+type fsmElement struct {
+	state    State
+	deadline time.Time // zero if the state has no timeout
+}
+
+type fsmMap[K comparable] struct {
+	config     Config
+	mu         sync.Mutex
+	timeouts   map[State]Timeout
+	data       map[K]fsmElement
+	eventsChan chan StateEvent[K]
+	quitChan   chan struct{}
+	clock      *coarseClock    // cached clock if Config.TimeResolution is set
+	suspend    suspendDetector // detects suspend of the process between cleaning runs
+	closed     bool            // true after Discard
+}
+
+// NewFSMMap creates new FSMMap with timeouts of states. Events are sent to channel with size Config.ExpiredElChanSize,
+// if it's bigger than 0. Timeouts are checked every Config.CleanJobInterval. It panics if a timeout is not positive,
+// because the key would transition without end.
+func NewFSMMap[K comparable](timeouts map[State]Timeout, configs ...Config) FSMMap[K] {
+	for state, timeout := range timeouts {
+		if timeout.After <= 0 {
+			panic(fmt.Sprintf("gocollections: timeout of state %q must be positive", state))
+		}
+	}
+	config := newConfig(configs)
+	m := &fsmMap[K]{
+		config:     config,
+		timeouts:   timeouts,
+		data:       make(map[K]fsmElement),
+		eventsChan: make(chan StateEvent[K], config.ExpiredElChanSize),
+		quitChan:   make(chan struct{}),
+		clock:      newCoarseClock(config),
+	}
+
+	startCleaner(config, m.quitChan, m.transition)
+
+	return m
+}
+
+// Set sets state of the key. Timeout of the state starts now.
+func (m *fsmMap[K]) Set(key K, state State) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return
+	}
+	m.data[key] = m.enter(state, m.clock.Now())
+}
+
+// Get returns state of the key.
+func (m *fsmMap[K]) Get(key K) (State, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return "", ErrClosed
+	}
+	e, found := m.advance(key, m.clock.Now())
+	if !found {
+		return "", &KeyError{Key: key}
+	}
+	return e.state, nil
+}
+
+// Del removes the key.
+func (m *fsmMap[K]) Del(key K) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return ErrClosed
+	}
+	if _, found := m.advance(key, m.clock.Now()); !found {
+		return &KeyError{Key: key}
+	}
+	delete(m.data, key)
+	return nil
+}
+
+// Size returns number of keys. Passed timeouts are applied first, so keys removed by timeout are not counted.
+func (m *fsmMap[K]) Size() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.advanceAll(m.clock.Now())
+	return len(m.data)
+}
+
+// Events returns channel with state transitions caused by timeouts. It's used only if Config.ExpiredElChanSize is
+// bigger than 0.
+func (m *fsmMap[K]) Events() chan StateEvent[K] {
+	return m.eventsChan
+}
+
+// Discard method stops the goroutine for checking timeouts and discards data in internal map.
+func (m *fsmMap[K]) Discard() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return
+	}
+	m.closed = true
+	close(m.quitChan)
+	m.clock.stop()
+	m.data = nil
+}
+
+// enter returns element in the state entered at time at.
+func (m *fsmMap[K]) enter(state State, at time.Time) fsmElement {
+	e := fsmElement{state: state}
+	if t, found := m.timeouts[state]; found {
+		e.deadline = at.Add(t.After)
+	}
+	return e
+}
+
+// transition moves keys with passed deadline to the next state.
+func (m *fsmMap[K]) transition() {
+	m.suspend.handle(m.config, &m.mu, m.shiftDeadlines)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.advanceAll(m.clock.Now())
+}
+
+// shiftDeadlines moves deadlines of all keys by d. It must be called with locked mutex.
+func (m *fsmMap[K]) shiftDeadlines(d time.Duration) {
+	for key, e := range m.data {
+		if !e.deadline.IsZero() {
+			e.deadline = e.deadline.Add(d)
+			m.data[key] = e
+		}
+	}
+}
+
+// advanceAll moves all keys with passed deadline to the next state. It must be called with locked mutex.
+func (m *fsmMap[K]) advanceAll(now time.Time) {
+	for key := range m.data {
+		m.advance(key, now)
+	}
+}
+
+// advance moves the key with passed deadline to the next state. If more timeouts passed since the last check, the key
+// transitions through all of them. It returns current element of the key and false if the key is not in the map. It
+// must be called with locked mutex.
+func (m *fsmMap[K]) advance(key K, now time.Time) (fsmElement, bool) {
+	e, found := m.data[key]
+	for found && !e.deadline.IsZero() && !e.deadline.After(now) {
+		next := m.timeouts[e.state].Next
+		sendDropOldest(m.eventsChan, StateEvent[K]{Key: key, From: e.state, To: next, At: e.deadline})
+		if next == "" {
+			delete(m.data, key)
+			return fsmElement{}, false
+		}
+		e = m.enter(next, e.deadline)
+		m.data[key] = e
+	}
+	return e, found
+}
@@ -0,0 +1,93 @@
+package gocollections
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFSMMap(t *testing.T) {
+	t.Parallel()
+
+	fsm := NewFSMMap[string](map[State]Timeout{
+		"connected": {After: 50 * time.Millisecond, Next: "idle"},
+		"idle":      {After: 50 * time.Millisecond, Next: ""},
+	}, Config{
+		CleanJobInterval:  10 * time.Millisecond,
+		ExpiredElChanSize: 10,
+	})
+	defer fsm.Discard()
+
+	fsm.Set("conn1", "connected")
+	fsm.Set("conn2", "closed")
+
+	ev := <-fsm.Events()
+	if ev.Key != "conn1" || ev.From != "connected" || ev.To != "idle" {
+		t.Errorf("Expect conn1 transition from connected to idle, but got %v", ev)
+	}
+	if state, _ := fsm.Get("conn1"); state != "idle" {
+		t.Errorf("want: %s, got: %s", "idle", state)
+	}
+
+	ev = <-fsm.Events()
+	if ev.To != "" {
+		t.Errorf("Expect conn1 removed, but got %v", ev)
+	}
+	if fsm.Size() != 1 {
+		t.Errorf("want: %d, got: %d", 1, fsm.Size())
+	}
+}
+
+func TestFSMMap_GetBetweenChecks(t *testing.T) {
+	t.Parallel()
+
+	// Timeouts are checked once a minute, so only reads apply them.
+	fsm := NewFSMMap[string](map[State]Timeout{
+		"connected": {After: 20 * time.Millisecond, Next: "idle"},
+		"idle":      {After: 20 * time.Millisecond, Next: ""},
+	})
+	defer fsm.Discard()
+
+	fsm.Set("conn1", "connected")
+	fsm.Set("conn2", "connected")
+	time.Sleep(30 * time.Millisecond)
+	if state, _ := fsm.Get("conn1"); state != "idle" {
+		t.Errorf("want: %s, got: %s", "idle", state)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if size := fsm.Size(); size != 0 {
+		t.Errorf("want: %d, got: %d", 0, size)
+	}
+}
+
+func TestNewFSMMap_InvalidTimeout(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expect panic for not positive timeout")
+		}
+	}()
+	NewFSMMap[string](map[State]Timeout{
+		"pending": {After: 0, Next: "pending"},
+	})
+}
+
+func TestFSMMap_Suspend(t *testing.T) {
+	t.Parallel()
+
+	fsm := NewFSMMap[string](map[State]Timeout{
+		"connected": {After: 10 * time.Second, Next: "idle"},
+	}, Config{SuspendPolicy: SuspendExpire})
+	defer fsm.Discard()
+	fsm.Set("conn1", "connected")
+
+	// Simulate timeouts lapsed during a minute of suspend.
+	m := fsm.(*fsmMap[string])
+	m.mu.Lock()
+	m.shiftDeadlines(m.config.suspendShift(time.Minute))
+	m.mu.Unlock()
+
+	if state, _ := fsm.Get("conn1"); state != "idle" {
+		t.Errorf("want: %s, got: %s", "idle", state)
+	}
+}
@@ -0,0 +1,114 @@
+// Package gocollectionsgossip replicates LWWMap between a handful of nodes by periodic gossip, so the nodes share a
+// soft-state cache without a central store. Transport between nodes is pluggable.
+package gocollectionsgossip
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	goc "github.com/martinspudich/go-collections"
+)
+
+// Message is exchanged between nodes. Request contains digest of the sender state and elements changed since the last
+// exchange with the peer. Response contains digest of the peer state and its whole state if digests differ.
+type Message[K comparable, V any] struct {
+	From    string
+	Digest  uint64
+	Entries []goc.LWWEntry[K, V]
+}
+
+// Transport delivers messages between nodes. Exchange sends request to the peer, which passes it to Node.Handle, and
+// returns its response.
+type Transport[K comparable, V any] interface {
+	Peers() []string
+	Exchange(ctx context.Context, peer string, req Message[K, V]) (Message[K, V], error)
+}
+
+// Node gossips state of the LWWMap with peers.
+type Node[K comparable, V any] struct {
+	name      string
+	m         goc.LWWMap[K, V]
+	transport Transport[K, V]
+	mu        sync.Mutex
+	lastSync  map[string]time.Time // start time of the last successful exchange with the peer
+}
+
+// NewNode creates new Node with name which gossips state of the map m by transport.
+func NewNode[K comparable, V any](name string, m goc.LWWMap[K, V], transport Transport[K, V]) *Node[K, V] {
+	return &Node[K, V]{
+		name:      name,
+		m:         m,
+		transport: transport,
+		lastSync:  make(map[string]time.Time),
+	}
+}
+
+// Run exchanges state with all peers every interval until the context ends. Failed exchanges are retried in the next
+// round.
+func (n *Node[K, V]) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			for _, peer := range n.transport.Peers() {
+				_ = n.SyncWith(ctx, peer)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// SyncWith exchanges state with the peer. It sends elements changed since the last exchange and receives whole state
+// of the peer if the states differ.
+func (n *Node[K, V]) SyncWith(ctx context.Context, peer string) error {
+	start := time.Now().Round(0)
+	n.mu.Lock()
+	since := n.lastSync[peer]
+	n.mu.Unlock()
+
+	state := n.m.State()
+	var delta []goc.LWWEntry[K, V]
+	for _, e := range state {
+		if !e.Timestamp.Before(since) {
+			delta = append(delta, e)
+		}
+	}
+	resp, err := n.transport.Exchange(ctx, peer, Message[K, V]{From: n.name, Digest: Digest(state), Entries: delta})
+	if err != nil {
+		return fmt.Errorf("gossip with %s: %w", peer, err)
+	}
+	n.m.Merge(resp.Entries)
+
+	n.mu.Lock()
+	n.lastSync[peer] = start
+	n.mu.Unlock()
+	return nil
+}
+
+// Handle handles request of the peer. It merges received elements and responds with whole state if the states still
+// differ.
+func (n *Node[K, V]) Handle(req Message[K, V]) Message[K, V] {
+	n.m.Merge(req.Entries)
+	state := n.m.State()
+	resp := Message[K, V]{From: n.name, Digest: Digest(state)}
+	if resp.Digest != req.Digest {
+		resp.Entries = state
+	}
+	return resp
+}
+
+// Digest returns hash of the state which doesn't depend on order of elements.
+func Digest[K comparable, V any](state []goc.LWWEntry[K, V]) uint64 {
+	var digest uint64
+	for _, e := range state {
+		h := fnv.New64a()
+		_, _ = fmt.Fprintf(h, "%v|%v|%d|%s|%t", e.Key, e.Value, e.Timestamp.UnixNano(), e.Node, e.Deleted)
+		digest ^= h.Sum64()
+	}
+	return digest
+}
@@ -0,0 +1,59 @@
+package gocollectionsgossip
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	goc "github.com/martinspudich/go-collections"
+)
+
+// localTransport delivers messages between nodes in the same process.
+type localTransport struct {
+	self  string
+	nodes map[string]*Node[string, string]
+}
+
+func (t *localTransport) Peers() []string {
+	var peers []string
+	for name := range t.nodes {
+		if name != t.self {
+			peers = append(peers, name)
+		}
+	}
+	return peers
+}
+
+func (t *localTransport) Exchange(ctx context.Context, peer string,
+	req Message[string, string]) (Message[string, string], error) {
+	return t.nodes[peer].Handle(req), nil
+}
+
+func TestGossip(t *testing.T) {
+	nodes := make(map[string]*Node[string, string])
+	maps := make(map[string]goc.LWWMap[string, string])
+	for _, name := range []string{"a", "b", "c"} {
+		maps[name] = goc.NewLWWMap[string, string](name, 10*time.Second)
+		defer maps[name].Discard()
+		nodes[name] = NewNode[string, string](name, maps[name], &localTransport{self: name, nodes: nodes})
+	}
+
+	maps["a"].Add("key1", "a1")
+	maps["c"].Add("key2", "c2")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	for _, n := range nodes {
+		go n.Run(ctx, 10*time.Millisecond)
+	}
+	<-ctx.Done()
+
+	for name, m := range maps {
+		if val, err := m.Get("key1"); err != nil || val != "a1" {
+			t.Errorf("node %s: want: %s, got: %s, %v", name, "a1", val, err)
+		}
+		if val, err := m.Get("key2"); err != nil || val != "c2" {
+			t.Errorf("node %s: want: %s, got: %s, %v", name, "c2", val, err)
+		}
+	}
+}
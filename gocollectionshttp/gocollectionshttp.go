@@ -0,0 +1,99 @@
+// Package gocollectionshttp provides HTTP export and import of go-collections, ex. for warm hand-off of caches between
+// old and new instances during rolling deploys.
+package gocollectionshttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	goc "github.com/martinspudich/go-collections"
+)
+
+// ErrIncompleteExport is returned by Import if the export stream ends without its trailer, ex. when the exporting
+// instance fails in the middle of the export.
+var ErrIncompleteExport = errors.New("incomplete export")
+
+// record is one exported element. Elements are streamed as JSON lines. The stream ends with trailer record with End
+// set and number of exported elements in Count, or error of the export in Error, so truncated stream is detected.
+type record[K comparable, V any] struct {
+	Key   K             `json:"key"`
+	Value V             `json:"value"`
+	TTL   time.Duration `json:"ttl"`
+	End   bool          `json:"end,omitempty"`
+	Count int           `json:"count,omitempty"`
+	Error string        `json:"error,omitempty"`
+}
+
+// ExportHandler returns handler which streams unexpired elements of the map with remaining durations as JSON lines.
+// Keys and values must be encodable by encoding/json. If an element can't be encoded, the export ends with error
+// trailer.
+func ExportHandler[K comparable, V any](m goc.TimeExpiredMap[K, V]) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		enc := json.NewEncoder(w)
+		count := 0
+		for _, e := range m.GetAllEntries() {
+			if err := enc.Encode(record[K, V]{Key: e.Key, Value: e.Value, TTL: e.TTL}); err != nil {
+				_ = enc.Encode(record[K, V]{End: true, Error: err.Error()})
+				return
+			}
+			count++
+		}
+		_ = enc.Encode(record[K, V]{End: true, Count: count})
+	})
+}
+
+// Import reads elements exported by ExportHandler from url and adds them to the map with their remaining durations.
+// It returns number of imported elements. It returns ErrIncompleteExport if the stream is truncated and error of the
+// export if it failed. Elements read before the error stay in the map. If client is nil, then http.DefaultClient is
+// used.
+func Import[K comparable, V any](ctx context.Context, client *http.Client, url string,
+	m goc.TimeExpiredMap[K, V]) (int, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("export %s: unexpected status %s", url, resp.Status)
+	}
+
+	count, received := 0, 0
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var rec record[K, V]
+		if err := dec.Decode(&rec); err != nil {
+			if errors.Is(err, io.EOF) {
+				return count, ErrIncompleteExport
+			}
+			return count, err
+		}
+		if rec.End {
+			switch {
+			case rec.Error != "":
+				return count, fmt.Errorf("export %s: %s", url, rec.Error)
+			case rec.Count != received:
+				return count, ErrIncompleteExport
+			}
+			return count, nil
+		}
+		received++
+		if rec.TTL <= 0 {
+			continue
+		}
+		m.AddWithDuration(rec.Key, rec.Value, rec.TTL)
+		count++
+	}
+}
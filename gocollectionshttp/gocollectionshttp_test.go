@@ -0,0 +1,62 @@
+package gocollectionshttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	goc "github.com/martinspudich/go-collections"
+)
+
+func TestExportImport(t *testing.T) {
+	src := goc.NewTimeExpiredMap[string, int](10 * time.Second)
+	defer src.Discard()
+	src.Add("a", 1)
+	src.AddWithDuration("b", 2, goc.NoExpiry)
+
+	server := httptest.NewServer(ExportHandler(src))
+	defer server.Close()
+
+	dst := goc.NewTimeExpiredMap[string, int](time.Minute)
+	defer dst.Discard()
+
+	count, err := Import(context.Background(), server.Client(), server.URL, dst)
+	if err != nil || count != 2 {
+		t.Fatalf("Expect 2 imported elements, but got %d, %v", count, err)
+	}
+	e, err := dst.GetEntry("a")
+	if err != nil || e.Value != 1 || e.TTL > 10*time.Second {
+		t.Errorf("Expect a with remaining duration, but got %+v, %v", e, err)
+	}
+	if e, _ := dst.GetEntry("b"); e.TTL != goc.NoExpiry {
+		t.Errorf("Expect b without expiration, but got %v", e.TTL)
+	}
+}
+
+func TestImport_Incomplete(t *testing.T) {
+	// Export fails on element which can't be encoded.
+	src := goc.NewTimeExpiredMap[string, any](10 * time.Second)
+	defer src.Discard()
+	src.Add("a", make(chan int))
+	failing := httptest.NewServer(ExportHandler(src))
+	defer failing.Close()
+
+	dst := goc.NewTimeExpiredMap[string, any](time.Minute)
+	defer dst.Discard()
+	if _, err := Import(context.Background(), failing.Client(), failing.URL, dst); err == nil {
+		t.Error("Expect error of failed export")
+	}
+
+	// Stream ends without trailer.
+	truncated := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"key":"a","value":1,"ttl":1000000000}` + "\n"))
+	}))
+	defer truncated.Close()
+	count, err := Import(context.Background(), truncated.Client(), truncated.URL, dst)
+	if !errors.Is(err, ErrIncompleteExport) || count != 1 {
+		t.Errorf("Expect ErrIncompleteExport after 1 element, but got %d, %v", count, err)
+	}
+}
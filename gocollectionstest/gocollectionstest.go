@@ -0,0 +1,34 @@
+// Package gocollectionstest provides utilities for testing code which uses go-collections.
+package gocollectionstest
+
+import (
+	"testing"
+	"time"
+
+	goc "github.com/martinspudich/go-collections"
+)
+
+// LeakTimeout is how long VerifyNoLeaks waits for cleaning goroutines to end after the test.
+var LeakTimeout = 1 * time.Second
+
+// VerifyNoLeaks fails the test if collections created during the test are not discarded or stopped at the end of the
+// test. It compares number of running cleaning goroutines at the start and at the end of the test, so it should not
+// be used in parallel tests.
+func VerifyNoLeaks(t testing.TB) {
+	t.Helper()
+	start := goc.ActiveCleaners()
+	t.Cleanup(func() {
+		deadline := time.Now().Add(LeakTimeout)
+		for {
+			active := goc.ActiveCleaners()
+			if active <= start {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Errorf("%d collection(s) created during the test were not discarded", active-start)
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+}
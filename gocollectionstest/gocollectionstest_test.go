@@ -0,0 +1,49 @@
+package gocollectionstest
+
+import (
+	"testing"
+	"time"
+
+	goc "github.com/martinspudich/go-collections"
+)
+
+// recorderTB records failures and cleanup functions of VerifyNoLeaks.
+type recorderTB struct {
+	testing.TB
+	failed   bool
+	cleanups []func()
+}
+
+func (r *recorderTB) Helper() {}
+
+func (r *recorderTB) Cleanup(f func()) {
+	r.cleanups = append(r.cleanups, f)
+}
+
+func (r *recorderTB) Errorf(format string, args ...any) {
+	r.failed = true
+}
+
+func TestVerifyNoLeaks(t *testing.T) {
+	VerifyNoLeaks(t)
+
+	tmap := goc.NewTimeExpiredMap[string, string](10 * time.Second)
+	tlist := goc.NewTimeExpiredList[string](10 * time.Second)
+	tmap.Discard()
+	tlist.Stop()
+}
+
+func TestVerifyNoLeaks_Leak(t *testing.T) {
+	rec := &recorderTB{TB: t}
+	VerifyNoLeaks(rec)
+
+	tmap := goc.NewTimeExpiredMap[string, string](10 * time.Second)
+	defer tmap.Discard()
+
+	for _, f := range rec.cleanups {
+		f()
+	}
+	if !rec.failed {
+		t.Error("VerifyNoLeaks should fail when map is not discarded")
+	}
+}
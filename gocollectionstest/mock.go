@@ -0,0 +1,745 @@
+package gocollectionstest
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	goc "github.com/martinspudich/go-collections"
+)
+
+// Call is a recorded method call of MockTimeExpiredMap.
+type Call struct {
+	Method string
+	Args   []any
+}
+
+// MockTimeExpiredMap is TimeExpiredMap for unit tests. It records method calls and returns errors set by SetError.
+// Elements don't expire in time, they expire only when Expire is called, so tests don't need sleeps. Methods which
+// wait in real map, like WaitFor or Drain, return immediately.
+type MockTimeExpiredMap[K comparable, V any] struct {
+	mu           sync.Mutex
+	data         map[K]goc.Entry[K, V]
+	errs         map[string]error
+	calls        []Call
+	closed       bool
+	expiredChan  chan V
+	replacedChan chan V
+}
+
+// NewMockTimeExpiredMap creates new MockTimeExpiredMap. Expired and replaced values are sent to channels of size
+// chanSize.
+func NewMockTimeExpiredMap[K comparable, V any](chanSize int) *MockTimeExpiredMap[K, V] {
+	return &MockTimeExpiredMap[K, V]{
+		data:         make(map[K]goc.Entry[K, V]),
+		errs:         make(map[string]error),
+		expiredChan:  make(chan V, chanSize),
+		replacedChan: make(chan V, chanSize),
+	}
+}
+
+// SetError sets error returned by every next call of the method. Nil error removes it.
+func (m *MockTimeExpiredMap[K, V]) SetError(method string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err == nil {
+		delete(m.errs, method)
+		return
+	}
+	m.errs[method] = err
+}
+
+// Calls returns recorded method calls in order.
+func (m *MockTimeExpiredMap[K, V]) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// Expire removes element of the key and sends its value to expired element channel, as if it expired.
+func (m *MockTimeExpiredMap[K, V]) Expire(key K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, found := m.data[key]
+	if !found {
+		return
+	}
+	delete(m.data, key)
+	select {
+	case m.expiredChan <- e.Value:
+	default:
+	}
+}
+
+// record records the call and returns error set for the method. It must be called with locked mutex.
+func (m *MockTimeExpiredMap[K, V]) record(method string, args ...any) error {
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+	return m.errs[method]
+}
+
+// set stores the value and sends replaced one to replaced element channel. It must be called with locked mutex.
+func (m *MockTimeExpiredMap[K, V]) set(key K, data V, duration time.Duration) (old V, existed bool) {
+	e, existed := m.data[key]
+	if existed {
+		old = e.Value
+		select {
+		case m.replacedChan <- old:
+		default:
+		}
+	}
+	m.data[key] = goc.Entry[K, V]{Key: key, Value: data, TTL: duration}
+	return old, existed
+}
+
+func (m *MockTimeExpiredMap[K, V]) Add(key K, data V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_ = m.record("Add", key, data)
+	m.set(key, data, 0)
+}
+
+func (m *MockTimeExpiredMap[K, V]) TryAdd(key K, data V) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("TryAdd", key, data); err != nil {
+		return err
+	}
+	m.set(key, data, 0)
+	return nil
+}
+
+func (m *MockTimeExpiredMap[K, V]) AddWithDuration(key K, data V, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_ = m.record("AddWithDuration", key, data, duration)
+	m.set(key, data, duration)
+}
+
+// AddWithContext adds element to the map. The context is ignored, use Expire to simulate its end.
+func (m *MockTimeExpiredMap[K, V]) AddWithContext(ctx context.Context, key K, data V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_ = m.record("AddWithContext", key, data)
+	m.set(key, data, 0)
+}
+
+// AddWithRefresher adds element like AddWithDuration. Refresher is never called, because elements don't expire in
+// time.
+func (m *MockTimeExpiredMap[K, V]) AddWithRefresher(key K, data V, duration time.Duration,
+	refresh func(key K, value V) (V, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_ = m.record("AddWithRefresher", key, data, duration)
+	m.set(key, data, duration)
+}
+
+// AddWithCallback adds element like Add. Callback is not called, elements expired by Expire are sent only to expired
+// element channel.
+func (m *MockTimeExpiredMap[K, V]) AddWithCallback(key K, data V, fn func(key K, value V)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_ = m.record("AddWithCallback", key, data)
+	m.set(key, data, 0)
+}
+
+func (m *MockTimeExpiredMap[K, V]) AddAll(entries []goc.Entry[K, V]) []error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("AddAll", entries); err != nil {
+		errs := make([]error, len(entries))
+		for i := range errs {
+			errs[i] = err
+		}
+		return errs
+	}
+	for _, e := range entries {
+		m.set(e.Key, e.Value, e.TTL)
+	}
+	return nil
+}
+
+func (m *MockTimeExpiredMap[K, V]) Swap(key K, data V) (old V, existed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_ = m.record("Swap", key, data)
+	return m.set(key, data, 0)
+}
+
+func (m *MockTimeExpiredMap[K, V]) Get(key K) (V, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var zero V
+	if err := m.record("Get", key); err != nil {
+		return zero, err
+	}
+	e, found := m.data[key]
+	if !found {
+		return zero, &goc.KeyError{Key: key}
+	}
+	return e.Value, nil
+}
+
+func (m *MockTimeExpiredMap[K, V]) TryGet(key K) (V, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var zero V
+	if err := m.record("TryGet", key); err != nil {
+		return zero, err
+	}
+	e, found := m.data[key]
+	if !found {
+		return zero, &goc.KeyError{Key: key}
+	}
+	return e.Value, nil
+}
+
+func (m *MockTimeExpiredMap[K, V]) GetManyConsistent(keys []K) map[K]V {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_ = m.record("GetManyConsistent", keys)
+	result := make(map[K]V, len(keys))
+	for _, key := range keys {
+		if e, found := m.data[key]; found {
+			result[key] = e.Value
+		}
+	}
+	return result
+}
+
+func (m *MockTimeExpiredMap[K, V]) GetEntry(key K) (goc.Entry[K, V], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("GetEntry", key); err != nil {
+		return goc.Entry[K, V]{}, err
+	}
+	e, found := m.data[key]
+	if !found {
+		return goc.Entry[K, V]{}, &goc.KeyError{Key: key}
+	}
+	return e, nil
+}
+
+// GetValidFor returns element by key. Elements of the mock don't expire, so minRemaining is ignored.
+func (m *MockTimeExpiredMap[K, V]) GetValidFor(key K, minRemaining time.Duration) (V, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var zero V
+	if err := m.record("GetValidFor", key, minRemaining); err != nil {
+		return zero, err
+	}
+	e, found := m.data[key]
+	if !found {
+		return zero, &goc.KeyError{Key: key}
+	}
+	return e.Value, nil
+}
+
+// WaitFor returns element by key. It doesn't wait if the key is not in the map.
+func (m *MockTimeExpiredMap[K, V]) WaitFor(ctx context.Context, key K) (V, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var zero V
+	if err := m.record("WaitFor", key); err != nil {
+		return zero, err
+	}
+	e, found := m.data[key]
+	if !found {
+		return zero, &goc.KeyError{Key: key}
+	}
+	return e.Value, nil
+}
+
+func (m *MockTimeExpiredMap[K, V]) GetAllEntries() []goc.Entry[K, V] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_ = m.record("GetAllEntries")
+	var result []goc.Entry[K, V]
+	for _, e := range m.data {
+		result = append(result, e)
+	}
+	return result
+}
+
+func (m *MockTimeExpiredMap[K, V]) Keys() []K {
+	var result []K
+	for _, e := range m.GetAllEntries() {
+		result = append(result, e.Key)
+	}
+	return result
+}
+
+func (m *MockTimeExpiredMap[K, V]) Values() []V {
+	var result []V
+	for _, e := range m.GetAllEntries() {
+		result = append(result, e.Value)
+	}
+	return result
+}
+
+func (m *MockTimeExpiredMap[K, V]) Range(fn func(key K, value V) bool) {
+	for _, e := range m.GetAllEntries() {
+		if !fn(e.Key, e.Value) {
+			return
+		}
+	}
+}
+
+func (m *MockTimeExpiredMap[K, V]) IterateByExpiry(order goc.SortOrder, fn func(e goc.Entry[K, V]) bool) {
+	entries := m.GetAllEntries()
+	sort.Slice(entries, func(i, j int) bool {
+		if order == goc.Descending {
+			return entries[i].TTL > entries[j].TTL
+		}
+		return entries[i].TTL < entries[j].TTL
+	})
+	for _, e := range entries {
+		if !fn(e) {
+			return
+		}
+	}
+}
+
+func (m *MockTimeExpiredMap[K, V]) Cursor() *goc.MapCursor[K, V] {
+	return goc.NewMapCursor[K, V](m)
+}
+
+func (m *MockTimeExpiredMap[K, V]) GetAllRaw() []goc.Entry[K, V] {
+	return m.GetAllEntries()
+}
+
+func (m *MockTimeExpiredMap[K, V]) SampleKeys(n int) []K {
+	var result []K
+	for _, e := range m.SampleEntries(n) {
+		result = append(result, e.Key)
+	}
+	return result
+}
+
+func (m *MockTimeExpiredMap[K, V]) SampleEntries(n int) []goc.Entry[K, V] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_ = m.record("SampleEntries", n)
+	var result []goc.Entry[K, V]
+	for _, e := range m.data {
+		if len(result) >= n {
+			break
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+func (m *MockTimeExpiredMap[K, V]) Contains(key K) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_ = m.record("Contains", key)
+	_, found := m.data[key]
+	return found
+}
+
+func (m *MockTimeExpiredMap[K, V]) Size() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_ = m.record("Size")
+	return len(m.data)
+}
+
+func (m *MockTimeExpiredMap[K, V]) SizeRaw() int {
+	return m.Size()
+}
+
+func (m *MockTimeExpiredMap[K, V]) SetTTL(key K, duration time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("SetTTL", key, duration); err != nil {
+		return err
+	}
+	e, found := m.data[key]
+	if !found {
+		return &goc.KeyError{Key: key}
+	}
+	e.TTL = duration
+	m.data[key] = e
+	return nil
+}
+
+func (m *MockTimeExpiredMap[K, V]) Persist(key K) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("Persist", key); err != nil {
+		return err
+	}
+	if _, found := m.data[key]; !found {
+		return &goc.KeyError{Key: key}
+	}
+	return nil
+}
+
+func (m *MockTimeExpiredMap[K, V]) Del(key K) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("Del", key); err != nil {
+		return err
+	}
+	if _, found := m.data[key]; !found {
+		return &goc.KeyError{Key: key}
+	}
+	delete(m.data, key)
+	return nil
+}
+
+func (m *MockTimeExpiredMap[K, V]) GetDel(key K) (V, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var zero V
+	if err := m.record("GetDel", key); err != nil {
+		return zero, err
+	}
+	e, found := m.data[key]
+	if !found {
+		return zero, &goc.KeyError{Key: key}
+	}
+	delete(m.data, key)
+	return e.Value, nil
+}
+
+// GetOrCompute returns value of the key or calls fn and stores its result. Unlike the real map, the mock is locked
+// while fn runs.
+func (m *MockTimeExpiredMap[K, V]) GetOrCompute(key K, fn func() (V, error)) (V, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var zero V
+	if err := m.record("GetOrCompute", key); err != nil {
+		return zero, err
+	}
+	if e, found := m.data[key]; found {
+		return e.Value, nil
+	}
+	value, err := fn()
+	if err != nil {
+		return zero, err
+	}
+	m.set(key, value, 0)
+	return value, nil
+}
+
+// SoftDel removes element like Del. Tombstones are not kept by the mock.
+func (m *MockTimeExpiredMap[K, V]) SoftDel(key K) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("SoftDel", key); err != nil {
+		return err
+	}
+	if _, found := m.data[key]; !found {
+		return &goc.KeyError{Key: key}
+	}
+	delete(m.data, key)
+	return nil
+}
+
+func (m *MockTimeExpiredMap[K, V]) IsDeleted(key K) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_ = m.record("IsDeleted", key)
+	return false
+}
+
+func (m *MockTimeExpiredMap[K, V]) AddUnlessDeleted(key K, data V) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("AddUnlessDeleted", key, data); err != nil {
+		return err
+	}
+	m.set(key, data, 0)
+	return nil
+}
+
+func (m *MockTimeExpiredMap[K, V]) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_ = m.record("Clear")
+	m.data = make(map[K]goc.Entry[K, V])
+}
+
+func (m *MockTimeExpiredMap[K, V]) Discard() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_ = m.record("Discard")
+	m.closed = true
+}
+
+func (m *MockTimeExpiredMap[K, V]) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_ = m.record("Stop")
+}
+
+func (m *MockTimeExpiredMap[K, V]) Start() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.record("Start")
+}
+
+func (m *MockTimeExpiredMap[K, V]) IsClosed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closed
+}
+
+// Drain returns immediately with error set by SetError.
+func (m *MockTimeExpiredMap[K, V]) Drain(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.record("Drain")
+}
+
+// WaitForEmpty returns immediately with error set by SetError.
+func (m *MockTimeExpiredMap[K, V]) WaitForEmpty(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.record("WaitForEmpty")
+}
+
+func (m *MockTimeExpiredMap[K, V]) Name() string {
+	return "mock"
+}
+
+func (m *MockTimeExpiredMap[K, V]) ExpiredElChan() chan V {
+	return m.expiredChan
+}
+
+func (m *MockTimeExpiredMap[K, V]) ReplacedElChan() chan V {
+	return m.replacedChan
+}
+
+// EvictedElChan returns nil, the mock doesn't evict elements.
+func (m *MockTimeExpiredMap[K, V]) EvictedElChan() chan V {
+	return nil
+}
+
+// AboutToExpireChan returns nil, the mock doesn't send elements about to expire.
+func (m *MockTimeExpiredMap[K, V]) AboutToExpireChan() chan goc.Entry[K, V] {
+	return nil
+}
+
+// WatchWhere returns watch without events, the mock doesn't send them.
+func (m *MockTimeExpiredMap[K, V]) WatchWhere(pred func(key K, value V) bool, bufferSize int) goc.Watch[K, V] {
+	return newStoppedWatch[K, V]()
+}
+
+// GroupStats returns no statistics, the mock doesn't support quota groups.
+func (m *MockTimeExpiredMap[K, V]) GroupStats() map[string]goc.GroupStats {
+	return nil
+}
+
+// stoppedWatch is watch without events.
+type stoppedWatch[K comparable, V any] struct {
+	ch chan goc.WatchEvent[K, V]
+}
+
+// newStoppedWatch returns watch with closed channel.
+func newStoppedWatch[K comparable, V any]() goc.Watch[K, V] {
+	ch := make(chan goc.WatchEvent[K, V])
+	close(ch)
+	return stoppedWatch[K, V]{ch: ch}
+}
+
+func (w stoppedWatch[K, V]) C() <-chan goc.WatchEvent[K, V] {
+	return w.ch
+}
+
+func (w stoppedWatch[K, V]) Stop() {}
+
+/*
+No-op cache
+*/
+
+// NoopCache is TimeExpiredMap which doesn't store anything, so every Get misses. It can be used to test code paths
+// without cache or to disable cache.
+type NoopCache[K comparable, V any] struct{}
+
+// NewNoopCache creates new NoopCache.
+func NewNoopCache[K comparable, V any]() goc.TimeExpiredMap[K, V] {
+	return NoopCache[K, V]{}
+}
+
+func (NoopCache[K, V]) Add(key K, data V) {}
+
+func (NoopCache[K, V]) TryAdd(key K, data V) error {
+	return nil
+}
+
+func (NoopCache[K, V]) AddWithDuration(key K, data V, duration time.Duration) {}
+
+func (NoopCache[K, V]) AddWithContext(ctx context.Context, key K, data V) {}
+
+func (NoopCache[K, V]) AddWithRefresher(key K, data V, duration time.Duration,
+	refresh func(key K, value V) (V, error)) {
+}
+
+func (NoopCache[K, V]) AddWithCallback(key K, data V, fn func(key K, value V)) {}
+
+func (NoopCache[K, V]) AddAll(entries []goc.Entry[K, V]) []error {
+	return nil
+}
+
+func (NoopCache[K, V]) Swap(key K, data V) (old V, existed bool) {
+	return old, false
+}
+
+func (NoopCache[K, V]) Get(key K) (V, error) {
+	var zero V
+	return zero, &goc.KeyError{Key: key}
+}
+
+func (NoopCache[K, V]) TryGet(key K) (V, error) {
+	var zero V
+	return zero, &goc.KeyError{Key: key}
+}
+
+func (NoopCache[K, V]) GetManyConsistent(keys []K) map[K]V {
+	return map[K]V{}
+}
+
+func (NoopCache[K, V]) GetEntry(key K) (goc.Entry[K, V], error) {
+	return goc.Entry[K, V]{}, &goc.KeyError{Key: key}
+}
+
+func (NoopCache[K, V]) GetValidFor(key K, minRemaining time.Duration) (V, error) {
+	var zero V
+	return zero, &goc.KeyError{Key: key}
+}
+
+func (NoopCache[K, V]) WaitFor(ctx context.Context, key K) (V, error) {
+	var zero V
+	return zero, &goc.KeyError{Key: key}
+}
+
+func (NoopCache[K, V]) GetAllEntries() []goc.Entry[K, V] {
+	return nil
+}
+
+func (NoopCache[K, V]) Keys() []K {
+	return nil
+}
+
+func (NoopCache[K, V]) Values() []V {
+	return nil
+}
+
+func (NoopCache[K, V]) Range(fn func(key K, value V) bool) {}
+
+func (NoopCache[K, V]) IterateByExpiry(order goc.SortOrder, fn func(e goc.Entry[K, V]) bool) {}
+
+func (c NoopCache[K, V]) Cursor() *goc.MapCursor[K, V] {
+	return goc.NewMapCursor[K, V](c)
+}
+
+func (NoopCache[K, V]) GetAllRaw() []goc.Entry[K, V] {
+	return nil
+}
+
+func (NoopCache[K, V]) SampleKeys(n int) []K {
+	return nil
+}
+
+func (NoopCache[K, V]) SampleEntries(n int) []goc.Entry[K, V] {
+	return nil
+}
+
+func (NoopCache[K, V]) Contains(key K) bool {
+	return false
+}
+
+func (NoopCache[K, V]) Size() int {
+	return 0
+}
+
+func (NoopCache[K, V]) SizeRaw() int {
+	return 0
+}
+
+func (NoopCache[K, V]) SetTTL(key K, duration time.Duration) error {
+	return &goc.KeyError{Key: key}
+}
+
+func (NoopCache[K, V]) Persist(key K) error {
+	return &goc.KeyError{Key: key}
+}
+
+func (NoopCache[K, V]) Del(key K) error {
+	return &goc.KeyError{Key: key}
+}
+
+func (NoopCache[K, V]) GetDel(key K) (V, error) {
+	var zero V
+	return zero, &goc.KeyError{Key: key}
+}
+
+// GetOrCompute calls fn every time, because nothing is cached.
+func (NoopCache[K, V]) GetOrCompute(key K, fn func() (V, error)) (V, error) {
+	return fn()
+}
+
+func (NoopCache[K, V]) SoftDel(key K) error {
+	return &goc.KeyError{Key: key}
+}
+
+func (NoopCache[K, V]) IsDeleted(key K) bool {
+	return false
+}
+
+func (NoopCache[K, V]) AddUnlessDeleted(key K, data V) error {
+	return nil
+}
+
+func (NoopCache[K, V]) Clear() {}
+
+func (NoopCache[K, V]) Discard() {}
+
+func (NoopCache[K, V]) Stop() {}
+
+func (NoopCache[K, V]) Start() error {
+	return nil
+}
+
+func (NoopCache[K, V]) IsClosed() bool {
+	return false
+}
+
+func (NoopCache[K, V]) Drain(ctx context.Context) error {
+	return nil
+}
+
+func (NoopCache[K, V]) WaitForEmpty(ctx context.Context) error {
+	return nil
+}
+
+func (NoopCache[K, V]) Name() string {
+	return "noop"
+}
+
+func (NoopCache[K, V]) ExpiredElChan() chan V {
+	return nil
+}
+
+func (NoopCache[K, V]) ReplacedElChan() chan V {
+	return nil
+}
+
+func (NoopCache[K, V]) EvictedElChan() chan V {
+	return nil
+}
+
+func (NoopCache[K, V]) AboutToExpireChan() chan goc.Entry[K, V] {
+	return nil
+}
+
+func (NoopCache[K, V]) WatchWhere(pred func(key K, value V) bool, bufferSize int) goc.Watch[K, V] {
+	return newStoppedWatch[K, V]()
+}
+
+func (NoopCache[K, V]) GroupStats() map[string]goc.GroupStats {
+	return nil
+}
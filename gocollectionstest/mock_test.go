@@ -0,0 +1,49 @@
+package gocollectionstest
+
+import (
+	"errors"
+	"testing"
+
+	goc "github.com/martinspudich/go-collections"
+)
+
+func TestMockTimeExpiredMap(t *testing.T) {
+	var tmap goc.TimeExpiredMap[string, string] = NewMockTimeExpiredMap[string, string](10)
+	mock := tmap.(*MockTimeExpiredMap[string, string])
+
+	tmap.Add("key1", "value1")
+	if val, err := tmap.Get("key1"); err != nil || val != "value1" {
+		t.Errorf("want: %s, got: %s, %v", "value1", val, err)
+	}
+
+	errBackend := errors.New("backend")
+	mock.SetError("Get", errBackend)
+	if _, err := tmap.Get("key1"); !errors.Is(err, errBackend) {
+		t.Errorf("Expect scripted error, but got %v", err)
+	}
+	mock.SetError("Get", nil)
+
+	mock.Expire("key1")
+	if _, err := tmap.Get("key1"); !errors.Is(err, goc.ErrKeyNotFound) {
+		t.Errorf("Expect ErrKeyNotFound, but got %v", err)
+	}
+	if val := <-tmap.ExpiredElChan(); val != "value1" {
+		t.Errorf("Expect expired value1, but got %s", val)
+	}
+
+	calls := mock.Calls()
+	if len(calls) != 4 || calls[0].Method != "Add" || calls[3].Method != "Get" {
+		t.Errorf("Unexpected recorded calls %v", calls)
+	}
+}
+
+func TestNoopCache(t *testing.T) {
+	tmap := NewNoopCache[string, string]()
+	tmap.Add("key1", "value1")
+	if _, err := tmap.Get("key1"); !errors.Is(err, goc.ErrKeyNotFound) {
+		t.Errorf("Expect ErrKeyNotFound, but got %v", err)
+	}
+	if tmap.Size() != 0 {
+		t.Errorf("Expect empty cache, but size is %d", tmap.Size())
+	}
+}
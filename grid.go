@@ -0,0 +1,134 @@
+package gocollections
+
+import "sync"
+
+/*
+Grid
+*/
+
+// Grid is a 2D collection with fixed number of rows and columns. Position x is a column and y is a row.
+type Grid[T any] interface {
+	Rows() int
+	Cols() int
+	Get(x, y int) (T, error)
+	Set(x, y int, value T) error
+	Fill(value T)
+	Transpose() Grid[T]
+	Neighbors(x, y int, diagonal bool, fn func(x, y int, value T) bool) error
+}
+
+type grid[T any] struct {
+	mu   sync.Mutex
+	rows int
+	cols int
+	data []T // elements row by row
+}
+
+// NewGrid creates new Grid with rows and cols filled with zero values. Negative sizes are treated as 0.
+func NewGrid[T any](rows, cols int) Grid[T] {
+	if rows < 0 {
+		rows = 0
+	}
+	if cols < 0 {
+		cols = 0
+	}
+	return &grid[T]{
+		rows: rows,
+		cols: cols,
+		data: make([]T, rows*cols),
+	}
+}
+
+// Rows returns number of rows.
+func (g *grid[T]) Rows() int {
+	return g.rows
+}
+
+// Cols returns number of columns.
+func (g *grid[T]) Cols() int {
+	return g.cols
+}
+
+// Get returns element on position x, y.
+func (g *grid[T]) Get(x, y int) (T, error) {
+	var result T
+	if err := g.checkBounds(x, y); err != nil {
+		return result, err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.data[y*g.cols+x], nil
+}
+
+// Set sets element on position x, y.
+func (g *grid[T]) Set(x, y int, value T) error {
+	if err := g.checkBounds(x, y); err != nil {
+		return err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.data[y*g.cols+x] = value
+	return nil
+}
+
+// Fill sets all elements to value.
+func (g *grid[T]) Fill(value T) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for i := range g.data {
+		g.data[i] = value
+	}
+}
+
+// Transpose returns new grid with rows and columns swapped.
+func (g *grid[T]) Transpose() Grid[T] {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	result := &grid[T]{
+		rows: g.cols,
+		cols: g.rows,
+		data: make([]T, len(g.data)),
+	}
+	for y := 0; y < g.rows; y++ {
+		for x := 0; x < g.cols; x++ {
+			result.data[x*result.cols+y] = g.data[y*g.cols+x]
+		}
+	}
+	return result
+}
+
+// Neighbors calls fn for every neighbor of position x, y inside the grid until fn returns false. If diagonal is true,
+// diagonal neighbors are included. The grid is locked during iteration, so fn must not call methods of the grid.
+func (g *grid[T]) Neighbors(x, y int, diagonal bool, fn func(x, y int, value T) bool) error {
+	if err := g.checkBounds(x, y); err != nil {
+		return err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			if (dx == 0 && dy == 0) || (!diagonal && dx != 0 && dy != 0) {
+				continue
+			}
+			nx, ny := x+dx, y+dy
+			if nx < 0 || nx >= g.cols || ny < 0 || ny >= g.rows {
+				continue
+			}
+			if !fn(nx, ny, g.data[ny*g.cols+nx]) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// checkBounds returns IndexError if position x, y is outside the grid.
+func (g *grid[T]) checkBounds(x, y int) error {
+	if x < 0 || x >= g.cols {
+		return &IndexError{Index: x, Len: g.cols}
+	}
+	if y < 0 || y >= g.rows {
+		return &IndexError{Index: y, Len: g.rows}
+	}
+	return nil
+}
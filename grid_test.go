@@ -0,0 +1,46 @@
+package gocollections
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGrid(t *testing.T) {
+	t.Parallel()
+
+	g := NewGrid[int](2, 3)
+	g.Fill(1)
+	if err := g.Set(2, 1, 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Set(3, 0, 5); !errors.Is(err, ErrIndexOutOfBound) {
+		t.Errorf("Expect ErrIndexOutOfBound but got %v", err)
+	}
+
+	tg := g.Transpose()
+	if tg.Rows() != 3 || tg.Cols() != 2 {
+		t.Errorf("Expect 3x2 grid, but got %dx%d", tg.Rows(), tg.Cols())
+	}
+	if val, err := tg.Get(1, 2); err != nil || val != 5 {
+		t.Errorf("want: %d, got: %d, %v", 5, val, err)
+	}
+
+	var count, sum int
+	_ = g.Neighbors(1, 1, true, func(x, y, value int) bool {
+		count++
+		sum += value
+		return true
+	})
+	if count != 5 || sum != 9 {
+		t.Errorf("Expect 5 neighbors with sum 9, but got %d with sum %d", count, sum)
+	}
+
+	count = 0
+	_ = g.Neighbors(0, 0, false, func(x, y, value int) bool {
+		count++
+		return true
+	})
+	if count != 2 {
+		t.Errorf("want: %d, got: %d", 2, count)
+	}
+}
@@ -0,0 +1,156 @@
+package gocollections
+
+import (
+	"fmt"
+	"hash/crc32"
+	"sort"
+	"sync"
+)
+
+/*
+Consistent Hash Ring
+*/
+
+// ConsistentHashRing maps keys to nodes so that adding or removing a node moves only small part of the keys. Every node
+// is placed on the ring multiple times as virtual nodes to spread keys evenly.
+type ConsistentHashRing[N comparable] interface {
+	AddNode(node N)
+	RemoveNode(node N)
+	GetNode(key string) (N, error)
+	Nodes() []N
+	Stats() RingStats[N]
+}
+
+// RingStats is statistics of the hash ring.
+type RingStats[N comparable] struct {
+	Nodes        int
+	VirtualNodes int
+	// Ownership is a fraction of the key space owned by the node.
+	Ownership map[N]float64
+	// LastMoved is a fraction of the key space which moved to another node by the last AddNode or RemoveNode.
+	LastMoved float64
+}
+
+type ringPoint[N comparable] struct {
+	hash uint32
+	node N
+}
+
+type consistentHashRing[N comparable] struct {
+	mu        sync.Mutex
+	replicas  int
+	points    []ringPoint[N] // sorted by hash
+	nodes     map[N]struct{}
+	lastMoved float64
+}
+
+// NewConsistentHashRing creates new ConsistentHashRing with number of virtual nodes per node.
+func NewConsistentHashRing[N comparable](virtualNodes int) ConsistentHashRing[N] {
+	if virtualNodes < 1 {
+		virtualNodes = 1
+	}
+	return &consistentHashRing[N]{
+		replicas: virtualNodes,
+		nodes:    make(map[N]struct{}),
+	}
+}
+
+// AddNode adds node to the ring.
+func (r *consistentHashRing[N]) AddNode(node N) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, found := r.nodes[node]; found {
+		return
+	}
+	r.nodes[node] = struct{}{}
+	for i := 0; i < r.replicas; i++ {
+		r.points = append(r.points, ringPoint[N]{hash: hashKey(fmt.Sprintf("%v#%d", node, i)), node: node})
+	}
+	sort.Slice(r.points, func(i, j int) bool {
+		return r.points[i].hash < r.points[j].hash
+	})
+	r.lastMoved = r.ownership()[node]
+}
+
+// RemoveNode removes node from the ring. Keys of the node move to the next nodes on the ring.
+func (r *consistentHashRing[N]) RemoveNode(node N) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, found := r.nodes[node]; !found {
+		return
+	}
+	r.lastMoved = r.ownership()[node]
+	delete(r.nodes, node)
+	points := r.points[:0]
+	for _, p := range r.points {
+		if p.node != node {
+			points = append(points, p)
+		}
+	}
+	r.points = points
+}
+
+// GetNode returns node for the key.
+func (r *consistentHashRing[N]) GetNode(key string) (N, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.points) == 0 {
+		var result N
+		return result, ErrNoNodes
+	}
+	h := hashKey(key)
+	i := sort.Search(len(r.points), func(i int) bool {
+		return r.points[i].hash >= h
+	})
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.points[i].node, nil
+}
+
+// Nodes returns all nodes of the ring.
+func (r *consistentHashRing[N]) Nodes() []N {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result := make([]N, 0, len(r.nodes))
+	for node := range r.nodes {
+		result = append(result, node)
+	}
+	return result
+}
+
+// Stats returns statistics of the ring.
+func (r *consistentHashRing[N]) Stats() RingStats[N] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return RingStats[N]{
+		Nodes:        len(r.nodes),
+		VirtualNodes: len(r.points),
+		Ownership:    r.ownership(),
+		LastMoved:    r.lastMoved,
+	}
+}
+
+// ownership returns fraction of the key space owned by every node. It must be called with locked mutex.
+func (r *consistentHashRing[N]) ownership() map[N]float64 {
+	result := make(map[N]float64, len(r.nodes))
+	if len(r.points) == 0 {
+		return result
+	}
+	const space = float64(1 << 32)
+	prev := r.points[len(r.points)-1].hash
+	for _, p := range r.points {
+		// Point owns keys from the previous point (exclusive) to itself, uint32 subtraction wraps around the ring.
+		result[p.node] += float64(p.hash-prev) / space
+		prev = p.hash
+	}
+	if len(r.points) == 1 {
+		result[r.points[0].node] = 1
+	}
+	return result
+}
+
+// hashKey returns hash of the key on the ring.
+func hashKey(key string) uint32 {
+	return crc32.ChecksumIEEE([]byte(key))
+}
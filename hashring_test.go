@@ -0,0 +1,50 @@
+package gocollections
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestConsistentHashRing(t *testing.T) {
+	t.Parallel()
+
+	ring := NewConsistentHashRing[string](100)
+	if _, err := ring.GetNode("key"); !errors.Is(err, ErrNoNodes) {
+		t.Errorf("Expect ErrNoNodes but got %v", err)
+	}
+
+	ring.AddNode("node1")
+	ring.AddNode("node2")
+	ring.AddNode("node3")
+
+	before := make(map[string]string)
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		before[key], _ = ring.GetNode(key)
+	}
+
+	ring.RemoveNode("node2")
+	for key, node := range before {
+		got, _ := ring.GetNode(key)
+		if node != "node2" && got != node {
+			t.Fatalf("Key %s moved from %s to %s, but only keys of removed node should move", key, node, got)
+		}
+	}
+
+	stats := ring.Stats()
+	if stats.Nodes != 2 || stats.VirtualNodes != 200 {
+		t.Errorf("Expect 2 nodes and 200 virtual nodes, but got %d and %d", stats.Nodes, stats.VirtualNodes)
+	}
+	var total float64
+	for _, o := range stats.Ownership {
+		total += o
+	}
+	if math.Abs(total-1) > 0.0001 {
+		t.Errorf("Expect total ownership 1, but got %f", total)
+	}
+	if stats.LastMoved <= 0 || stats.LastMoved >= 1 {
+		t.Errorf("Expect moved fraction between 0 and 1, but got %f", stats.LastMoved)
+	}
+}
@@ -0,0 +1,40 @@
+package gocollections
+
+/*
+Size histogram
+*/
+
+// SizeBucket is a bucket of SizeHistogram. It counts values with size greater than half of MaxSize and up to MaxSize.
+// First bucket counts values with size up to 1.
+type SizeBucket struct {
+	MaxSize int
+	Count   int
+	Total   int // sum of sizes of counted values
+}
+
+// SizeHistogram returns distribution of sizes of unexpired values in power-of-two buckets, ex. to find oversized values
+// which should be compressed or not cached. Size of value is returned by sizeOf, ex. length of encoded value. Buckets
+// are sorted by MaxSize and empty buckets between them are included.
+func SizeHistogram[K comparable, V any](m MapReader[K, V], sizeOf func(value V) int) []SizeBucket {
+	var result []SizeBucket
+	m.Range(func(key K, value V) bool {
+		size := sizeOf(value)
+		i := sizeBucket(size)
+		for len(result) <= i {
+			result = append(result, SizeBucket{MaxSize: 1 << len(result)})
+		}
+		result[i].Count++
+		result[i].Total += size
+		return true
+	})
+	return result
+}
+
+// sizeBucket returns index of bucket for the size.
+func sizeBucket(size int) int {
+	i := 0
+	for 1<<i < size {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,34 @@
+package gocollections
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSizeHistogram(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](time.Minute)
+	defer tmap.Discard()
+	tmap.Add("key1", "")
+	tmap.Add("key2", "abc")
+	tmap.Add("key3", "abcd")
+	tmap.Add("key4", "abcdefghij")
+
+	got := SizeHistogram[string, string](tmap, func(value string) int { return len(value) })
+	want := []SizeBucket{
+		{MaxSize: 1, Count: 1, Total: 0},
+		{MaxSize: 2},
+		{MaxSize: 4, Count: 2, Total: 7},
+		{MaxSize: 8},
+		{MaxSize: 16, Count: 1, Total: 10},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("want: %v, got: %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("bucket %d want: %v, got: %v", i, want[i], got[i])
+		}
+	}
+}
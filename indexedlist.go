@@ -0,0 +1,191 @@
+package gocollections
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+/*
+Indexed List
+*/
+
+// IndexedList is a list of elements with unique keys. It combines O(1) lookup by key with iteration in insertion
+// order, like LinkedHashMap in Java.
+type IndexedList[K comparable, V any] interface {
+	Put(key K, value V)
+	Get(key K) (V, error)
+	GetAt(index int) (K, V, error)
+	ContainsKey(key K) bool
+	Del(key K) error
+	Keys() []K
+	Values() []V
+	Iterate(fn func(key K, value V) bool)
+	Size() int
+	Clear()
+}
+
+type indexedElement[K comparable, V any] struct {
+	key       K
+	value     V
+	expiredAt time.Time
+}
+
+type indexedList[K comparable, V any] struct {
+	mu       sync.Mutex
+	duration time.Duration // element duration, 0 means elements don't expire
+	order    *list.List    // elements in insertion order
+	index    map[K]*list.Element
+}
+
+// NewIndexedList creates new IndexedList.
+func NewIndexedList[K comparable, V any]() IndexedList[K, V] {
+	return NewTimeExpiredIndexedList[K, V](0)
+}
+
+// NewTimeExpiredIndexedList creates new IndexedList which elements expire after duration. Expired elements are removed
+// when the list is accessed, so it doesn't run any goroutine.
+func NewTimeExpiredIndexedList[K comparable, V any](duration time.Duration) IndexedList[K, V] {
+	return &indexedList[K, V]{
+		duration: duration,
+		order:    list.New(),
+		index:    make(map[K]*list.Element),
+	}
+}
+
+// Put adds element with key to the end of the list. If the key is already in the list, the element is moved to the
+// end of the list with the new value.
+func (l *indexedList[K, V]) Put(key K, value V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.removeExpired()
+	if e, found := l.index[key]; found {
+		l.order.Remove(e)
+	}
+	el := &indexedElement[K, V]{key: key, value: value}
+	if l.duration > 0 {
+		el.expiredAt = time.Now().Add(l.duration)
+	}
+	l.index[key] = l.order.PushBack(el)
+}
+
+// Get returns element by key.
+func (l *indexedList[K, V]) Get(key K) (V, error) {
+	var result V
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.removeExpired()
+	e, found := l.index[key]
+	if !found {
+		return result, &KeyError{Key: key}
+	}
+	return e.Value.(*indexedElement[K, V]).value, nil
+}
+
+// GetAt returns key and value of the element by index in insertion order.
+func (l *indexedList[K, V]) GetAt(index int) (K, V, error) {
+	var key K
+	var value V
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.removeExpired()
+	if index < 0 || index >= l.order.Len() {
+		return key, value, &IndexError{Index: index, Len: l.order.Len()}
+	}
+	e := l.order.Front()
+	for i := 0; i < index; i++ {
+		e = e.Next()
+	}
+	el := e.Value.(*indexedElement[K, V])
+	return el.key, el.value, nil
+}
+
+// ContainsKey returns true if key is in the list.
+func (l *indexedList[K, V]) ContainsKey(key K) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.removeExpired()
+	_, found := l.index[key]
+	return found
+}
+
+// Del removes element by key.
+func (l *indexedList[K, V]) Del(key K) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.removeExpired()
+	e, found := l.index[key]
+	if !found {
+		return &KeyError{Key: key}
+	}
+	l.order.Remove(e)
+	delete(l.index, key)
+	return nil
+}
+
+// Keys returns keys in insertion order.
+func (l *indexedList[K, V]) Keys() []K {
+	var result []K
+	l.Iterate(func(key K, value V) bool {
+		result = append(result, key)
+		return true
+	})
+	return result
+}
+
+// Values returns values in insertion order.
+func (l *indexedList[K, V]) Values() []V {
+	var result []V
+	l.Iterate(func(key K, value V) bool {
+		result = append(result, value)
+		return true
+	})
+	return result
+}
+
+// Iterate calls fn for every element in insertion order until fn returns false. The list is locked during iteration,
+// so fn must not call methods of the list.
+func (l *indexedList[K, V]) Iterate(fn func(key K, value V) bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.removeExpired()
+	for e := l.order.Front(); e != nil; e = e.Next() {
+		el := e.Value.(*indexedElement[K, V])
+		if !fn(el.key, el.value) {
+			return
+		}
+	}
+}
+
+// Size returns size of the list.
+func (l *indexedList[K, V]) Size() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.removeExpired()
+	return l.order.Len()
+}
+
+// Clear method clears all elements from the list.
+func (l *indexedList[K, V]) Clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.order.Init()
+	l.index = make(map[K]*list.Element)
+}
+
+// removeExpired removes expired elements from the front of the list. All elements have the same duration, so the
+// oldest elements expire first. It must be called with locked mutex.
+func (l *indexedList[K, V]) removeExpired() {
+	if l.duration <= 0 {
+		return
+	}
+	now := time.Now()
+	for e := l.order.Front(); e != nil; e = l.order.Front() {
+		el := e.Value.(*indexedElement[K, V])
+		if el.expiredAt.After(now) {
+			return
+		}
+		l.order.Remove(e)
+		delete(l.index, el.key)
+	}
+}
@@ -0,0 +1,54 @@
+package gocollections
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIndexedList(t *testing.T) {
+	t.Parallel()
+
+	ilist := NewIndexedList[string, int]()
+	ilist.Put("a", 1)
+	ilist.Put("b", 2)
+	ilist.Put("c", 3)
+	ilist.Put("a", 4)
+
+	if want := []string{"b", "c", "a"}; !reflect.DeepEqual(ilist.Keys(), want) {
+		t.Errorf("want: %v, got: %v", want, ilist.Keys())
+	}
+	if val, err := ilist.Get("a"); err != nil || val != 4 {
+		t.Errorf("want: %d, got: %d, %v", 4, val, err)
+	}
+	key, val, err := ilist.GetAt(1)
+	if err != nil || key != "c" || val != 3 {
+		t.Errorf("Expect c=3 at index 1, but got %s=%d, %v", key, val, err)
+	}
+	if err = ilist.Del("b"); err != nil {
+		t.Fatal(err)
+	}
+	if ilist.ContainsKey("b") || ilist.Size() != 2 {
+		t.Errorf("Expect b removed, but got keys %v", ilist.Keys())
+	}
+	if _, _, err = ilist.GetAt(2); !errors.Is(err, ErrIndexOutOfBound) {
+		t.Errorf("Expect ErrIndexOutOfBound but got %v", err)
+	}
+}
+
+func TestTimeExpiredIndexedList(t *testing.T) {
+	t.Parallel()
+
+	ilist := NewTimeExpiredIndexedList[string, int](50 * time.Millisecond)
+	ilist.Put("a", 1)
+	time.Sleep(100 * time.Millisecond)
+	ilist.Put("b", 2)
+
+	if ilist.ContainsKey("a") {
+		t.Error("key a should expire")
+	}
+	if ilist.Size() != 1 {
+		t.Errorf("want: %d, got: %d", 1, ilist.Size())
+	}
+}
@@ -0,0 +1,106 @@
+package gocollections
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+/*
+Lease Map
+*/
+
+// Lease is a lease of the key in LeaseMap.
+type Lease[K comparable] struct {
+	Key K
+	ID  uint64
+	TTL time.Duration
+}
+
+// LeaseMap is a map of leases. Only one lease of the key can be acquired at a time. Lease expires if it's not renewed
+// within its TTL. Expired leases are sent to expired channel. It's built on TimeExpiredMap, so it's running goroutine
+// which removes expired leases. To stop this goroutine call Discard() method when this map is not needed any more.
+type LeaseMap[K comparable] interface {
+	Acquire(key K, ttl time.Duration) (Lease[K], bool)
+	Renew(lease Lease[K]) error
+	Release(lease Lease[K]) error
+	IsHeld(key K) bool
+	ExpiredChan() chan Lease[K]
+	Discard()
+}
+
+type leaseMap[K comparable] struct {
+	mu     sync.Mutex
+	lastID uint64
+	leases TimeExpiredMap[K, Lease[K]]
+}
+
+// NewLeaseMap creates new LeaseMap. Expired leases are sent to channel with size Config.ExpiredElChanSize.
+func NewLeaseMap[K comparable](configs ...Config) LeaseMap[K] {
+	return &leaseMap[K]{
+		leases: NewTimeExpiredMap[K, Lease[K]](0, configs...),
+	}
+}
+
+// Acquire acquires lease of the key for ttl. It returns false if the key already has unexpired lease or the map was
+// discarded.
+func (m *leaseMap[K]) Acquire(key K, ttl time.Duration) (Lease[K], bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.leases.IsClosed() || m.leases.Contains(key) {
+		return Lease[K]{}, false
+	}
+	m.lastID++
+	lease := Lease[K]{Key: key, ID: m.lastID, TTL: ttl}
+	m.leases.AddWithDuration(key, lease, ttl)
+	return lease, true
+}
+
+// Renew extends the lease by its TTL. It returns ErrLeaseNotFound if the lease expired or was released.
+func (m *leaseMap[K]) Renew(lease Lease[K]) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.check(lease); err != nil {
+		return err
+	}
+	m.leases.AddWithDuration(lease.Key, lease, lease.TTL)
+	return nil
+}
+
+// Release releases the lease, so the key can be acquired again. It returns ErrLeaseNotFound if the lease expired or
+// was already released.
+func (m *leaseMap[K]) Release(lease Lease[K]) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.check(lease); err != nil {
+		return err
+	}
+	return m.leases.Del(lease.Key)
+}
+
+// check returns error if the lease is not the current lease of its key. It must be called with locked mutex.
+func (m *leaseMap[K]) check(lease Lease[K]) error {
+	current, err := m.leases.Get(lease.Key)
+	if errors.Is(err, ErrClosed) {
+		return err
+	}
+	if err != nil || current.ID != lease.ID {
+		return ErrLeaseNotFound
+	}
+	return nil
+}
+
+// IsHeld returns true if the key has unexpired lease.
+func (m *leaseMap[K]) IsHeld(key K) bool {
+	return m.leases.Contains(key)
+}
+
+// ExpiredChan returns channel with expired leases.
+func (m *leaseMap[K]) ExpiredChan() chan Lease[K] {
+	return m.leases.ExpiredElChan()
+}
+
+// Discard method stops the goroutine for removing expired leases and discards all leases.
+func (m *leaseMap[K]) Discard() {
+	m.leases.Discard()
+}
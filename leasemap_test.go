@@ -0,0 +1,60 @@
+package gocollections
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLeaseMap(t *testing.T) {
+	t.Parallel()
+
+	lm := NewLeaseMap[string](Config{
+		CleanJobInterval:  20 * time.Millisecond,
+		ExpiredElChanSize: 10,
+	})
+	defer lm.Discard()
+
+	lease, ok := lm.Acquire("resource", 50*time.Millisecond)
+	if !ok {
+		t.Fatal("Expect lease acquired")
+	}
+	if _, ok = lm.Acquire("resource", time.Second); ok {
+		t.Error("Expect second lease of the same key not acquired")
+	}
+	if err := lm.Renew(lease); err != nil {
+		t.Fatal(err)
+	}
+	if err := lm.Release(lease); err != nil {
+		t.Fatal(err)
+	}
+	if err := lm.Renew(lease); !errors.Is(err, ErrLeaseNotFound) {
+		t.Errorf("Expect ErrLeaseNotFound but got %v", err)
+	}
+
+	lease, ok = lm.Acquire("resource", 50*time.Millisecond)
+	if !ok {
+		t.Fatal("Expect lease acquired after release")
+	}
+	select {
+	case expired := <-lm.ExpiredChan():
+		if expired.ID != lease.ID {
+			t.Errorf("want: %d, got: %d", lease.ID, expired.ID)
+		}
+	case <-time.After(1 * time.Second):
+		t.Error("No expired lease in timeout")
+	}
+	if lm.IsHeld("resource") {
+		t.Error("Expect lease expired")
+	}
+}
+
+func TestLeaseMap_AfterDiscard(t *testing.T) {
+	t.Parallel()
+
+	lm := NewLeaseMap[string]()
+	lm.Discard()
+	if _, ok := lm.Acquire("resource", time.Second); ok {
+		t.Error("Expect lease not acquired after Discard")
+	}
+}
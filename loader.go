@@ -0,0 +1,140 @@
+package gocollections
+
+import (
+	"context"
+	"sync"
+)
+
+/*
+Parallel loader
+*/
+
+// LoadMissing loads keys which are not in the map by function load and adds them to the map. At most concurrency keys
+// are loaded in parallel. It stops loading on the first error or when the context ends and returns the error. Keys
+// loaded before are kept in the map.
+func LoadMissing[K comparable, V any](ctx context.Context, m TimeExpiredMap[K, V], keys []K, concurrency int,
+	load func(ctx context.Context, key K) (V, error)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		errOnce  sync.Once
+		firstErr error
+	)
+	loadParallel(ctx, m, keys, concurrency, load, m.Contains, func(key K, err error) {
+		if err == nil {
+			return
+		}
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	})
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// WarmOptions configures Warm.
+type WarmOptions struct {
+	// Concurrency is maximum number of parallel loads. Default is 1.
+	Concurrency int
+	// MaxSize stops warming when the map has MaxSize elements, including loads in progress, so the map doesn't grow
+	// over it by parallel loads. Keys which are not loaded because of it are counted in WarmProgress.Skipped. It's not
+	// used if it's 0.
+	MaxSize int
+	// OnProgress is called after every load with current progress. Calls are not concurrent.
+	OnProgress func(progress WarmProgress)
+}
+
+// WarmProgress is progress of Warm.
+type WarmProgress struct {
+	Total   int // number of keys to warm
+	Loaded  int // number of loaded keys
+	Failed  int // number of keys which load failed
+	Skipped int // number of keys which were not loaded, because the map reached WarmOptions.MaxSize
+}
+
+// Warm populates the map with keys loaded by function load, ex. before the service starts serving traffic. Unlike
+// LoadMissing, failed keys don't stop warming, they are counted in the progress. It returns final progress and context
+// error if the context ends before all keys are loaded.
+func Warm[K comparable, V any](ctx context.Context, m TimeExpiredMap[K, V], keys []K,
+	load func(ctx context.Context, key K) (V, error), opts WarmOptions) (WarmProgress, error) {
+	var (
+		mu       sync.Mutex
+		progress = WarmProgress{Total: len(keys)}
+		inFlight int // number of loads in progress
+	)
+	isFull := func(key K) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		if opts.MaxSize > 0 && m.Size()+inFlight >= opts.MaxSize {
+			progress.Skipped++
+			return true
+		}
+		inFlight++
+		return false
+	}
+	loadParallel(ctx, m, keys, opts.Concurrency, load, isFull, func(key K, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		inFlight--
+		if err != nil {
+			progress.Failed++
+		} else {
+			progress.Loaded++
+		}
+		if opts.OnProgress != nil {
+			opts.OnProgress(progress)
+		}
+	})
+
+	mu.Lock()
+	defer mu.Unlock()
+	return progress, ctx.Err()
+}
+
+// loadParallel loads keys by function load in at most concurrency goroutines and adds loaded elements to the map. Keys
+// for which skip returns true are not loaded. Function done is called concurrently with the key and error of every
+// load. Loading stops when the context ends.
+func loadParallel[K comparable, V any](ctx context.Context, m TimeExpiredMap[K, V], keys []K, concurrency int,
+	load func(ctx context.Context, key K) (V, error), skip func(key K) bool, done func(key K, err error)) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var wg sync.WaitGroup
+	keyChan := make(chan K)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range keyChan {
+				if ctx.Err() != nil {
+					continue
+				}
+				value, err := load(ctx, key)
+				if err == nil {
+					m.Add(key, value)
+				}
+				done(key, err)
+			}
+		}()
+	}
+
+send:
+	for _, key := range keys {
+		if skip(key) {
+			continue
+		}
+		select {
+		case keyChan <- key:
+		case <-ctx.Done():
+			break send
+		}
+	}
+	close(keyChan)
+	wg.Wait()
+}
@@ -0,0 +1,108 @@
+package gocollections
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLoadMissing(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[int, string](10 * time.Second)
+	defer tmap.Discard()
+	tmap.Add(0, "cached")
+
+	var running, maxRunning atomic.Int32
+	load := func(ctx context.Context, key int) (string, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return strconv.Itoa(key), nil
+	}
+
+	keys := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	if err := LoadMissing(context.Background(), tmap, keys, 3, load); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if tmap.Size() != 10 {
+		t.Errorf("Expect 10 elements, but got %d", tmap.Size())
+	}
+	if val, _ := tmap.Get(0); val != "cached" {
+		t.Errorf("Expect cached element not loaded again, but got %s", val)
+	}
+	if maxRunning.Load() > 3 {
+		t.Errorf("Expect at most 3 parallel loads, but got %d", maxRunning.Load())
+	}
+
+	errBackend := errors.New("backend")
+	err := LoadMissing(context.Background(), tmap, []int{10, 11}, 2,
+		func(ctx context.Context, key int) (string, error) {
+			return "", errBackend
+		})
+	if !errors.Is(err, errBackend) {
+		t.Errorf("Expect backend error, but got %v", err)
+	}
+}
+
+func TestWarm(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[int, string](10 * time.Second)
+	defer tmap.Discard()
+
+	var reports atomic.Int32
+	errBackend := errors.New("backend")
+	progress, err := Warm(context.Background(), tmap, []int{1, 2, 3, 4},
+		func(ctx context.Context, key int) (string, error) {
+			if key == 3 {
+				return "", errBackend
+			}
+			return strconv.Itoa(key), nil
+		}, WarmOptions{
+			Concurrency: 2,
+			OnProgress: func(progress WarmProgress) {
+				reports.Add(1)
+			},
+		})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if progress.Total != 4 || progress.Loaded != 3 || progress.Failed != 1 {
+		t.Errorf("Unexpected progress %+v", progress)
+	}
+	if reports.Load() != 4 || tmap.Size() != 3 {
+		t.Errorf("Expect 4 progress reports and 3 elements, but got %d and %d", reports.Load(), tmap.Size())
+	}
+}
+
+func TestWarm_MaxSize(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[int, int](10 * time.Second)
+	defer tmap.Discard()
+
+	keys := make([]int, 20)
+	for i := range keys {
+		keys[i] = i
+	}
+	progress, err := Warm(context.Background(), tmap, keys, func(ctx context.Context, key int) (int, error) {
+		time.Sleep(time.Millisecond)
+		return key, nil
+	}, WarmOptions{Concurrency: 4, MaxSize: 5})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if tmap.Size() != 5 || progress.Loaded != 5 || progress.Skipped != 15 {
+		t.Errorf("Expect 5 loaded and 15 skipped keys, but got size %d and progress %+v", tmap.Size(), progress)
+	}
+}
@@ -0,0 +1,161 @@
+package gocollections
+
+import (
+	"sync"
+	"time"
+)
+
+/*
+Log
+*/
+
+// Log is an append-only log. Every appended entry gets monotonically increasing offset. Entries older than retention
+// duration are truncated by running goroutine. To stop this goroutine call Discard() method when this log is not
+// needed any more.
+type Log[T any] interface {
+	Append(value T) uint64
+	ReadFrom(offset uint64) ([]LogEntry[T], error)
+	FirstOffset() uint64
+	NextOffset() uint64
+	Size() int
+	Discard()
+}
+
+// LogEntry is an entry of the log with its offset and append time.
+type LogEntry[T any] struct {
+	Offset uint64
+	Value  T
+	Time   time.Time
+}
+
+// logElement is an entry of the log with time when it's truncated.
+type logElement[T any] struct {
+	entry     LogEntry[T]
+	expiredAt time.Time
+}
+
+type appendLog[T any] struct {
+	config    Config
+	mu        sync.Mutex
+	retention time.Duration
+	entries   []logElement[T] // entries ordered by offset
+	next      uint64          // offset of the next appended entry
+	quitChan  chan struct{}
+	clock     *coarseClock    // cached clock if Config.TimeResolution is set
+	suspend   suspendDetector // detects suspend of the process between cleaning runs
+	closed    bool            // true after Discard
+}
+
+// NewLog creates new Log which keeps entries for retention duration. It runs goroutine for truncation of old entries.
+func NewLog[T any](retention time.Duration, configs ...Config) Log[T] {
+	config := newConfig(configs)
+	l := &appendLog[T]{
+		config:    config,
+		retention: retention,
+		quitChan:  make(chan struct{}),
+		clock:     newCoarseClock(config),
+	}
+
+	startCleaner(config, l.quitChan, l.truncate)
+
+	return l
+}
+
+// Append adds value to the end of the log and returns its offset.
+func (l *appendLog[T]) Append(value T) uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	offset := l.next
+	if l.closed {
+		return offset
+	}
+	entry := LogEntry[T]{Offset: offset, Value: value, Time: l.clock.Now()}
+	l.entries = append(l.entries, logElement[T]{entry: entry, expiredAt: l.clock.expiry(l.retention)})
+	l.next++
+	return offset
+}
+
+// ReadFrom returns entries from offset to the end of the log. It returns ErrTruncated if the offset was already
+// truncated.
+func (l *appendLog[T]) ReadFrom(offset uint64) ([]LogEntry[T], error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil, ErrClosed
+	}
+	if offset > l.next {
+		return nil, &IndexError{Index: int(offset), Len: int(l.next)}
+	}
+	first := l.firstOffset()
+	if offset < first {
+		return nil, ErrTruncated
+	}
+	entries := l.entries[offset-first:]
+	result := make([]LogEntry[T], len(entries))
+	for i, e := range entries {
+		result[i] = e.entry
+	}
+	return result, nil
+}
+
+// FirstOffset returns offset of the oldest entry in the log.
+func (l *appendLog[T]) FirstOffset() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.firstOffset()
+}
+
+// firstOffset returns offset of the oldest entry. It must be called with locked mutex.
+func (l *appendLog[T]) firstOffset() uint64 {
+	return l.next - uint64(len(l.entries))
+}
+
+// NextOffset returns offset of the next appended entry.
+func (l *appendLog[T]) NextOffset() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.next
+}
+
+// Size returns number of entries in the log.
+func (l *appendLog[T]) Size() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.entries)
+}
+
+// Discard method stops the goroutine for truncation and discards entries.
+func (l *appendLog[T]) Discard() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return
+	}
+	l.closed = true
+	close(l.quitChan)
+	l.clock.stop()
+	l.entries = nil
+}
+
+// truncate removes entries older than retention.
+func (l *appendLog[T]) truncate() {
+	l.suspend.handle(l.config, &l.mu, l.shiftExpirations)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.clock.Now()
+	n := 0
+	for n < len(l.entries) && l.entries[n].expiredAt.Before(now) {
+		n++
+	}
+	if n > 0 {
+		// Copy to the new slice, so memory of truncated entries is released.
+		l.entries = append([]logElement[T](nil), l.entries[n:]...)
+	}
+}
+
+// shiftExpirations moves truncation of all entries by d. It must be called with locked mutex.
+func (l *appendLog[T]) shiftExpirations(d time.Duration) {
+	for i := range l.entries {
+		l.entries[i].expiredAt = shiftExpiry(l.entries[i].expiredAt, d)
+	}
+}
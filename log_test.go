@@ -0,0 +1,58 @@
+package gocollections
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLog(t *testing.T) {
+	t.Parallel()
+
+	tlog := NewLog[string](100*time.Millisecond, Config{
+		CleanJobInterval: 50 * time.Millisecond,
+	})
+	defer tlog.Discard()
+
+	if offset := tlog.Append("event1"); offset != 0 {
+		t.Errorf("want: %d, got: %d", 0, offset)
+	}
+	time.Sleep(200 * time.Millisecond)
+	tlog.Append("event2")
+	if offset := tlog.Append("event3"); offset != 2 {
+		t.Errorf("want: %d, got: %d", 2, offset)
+	}
+
+	if _, err := tlog.ReadFrom(0); !errors.Is(err, ErrTruncated) {
+		t.Errorf("Expect ErrTruncated but got %v", err)
+	}
+	entries, err := tlog.ReadFrom(tlog.FirstOffset())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Value != "event2" || entries[1].Offset != 2 {
+		t.Errorf("Expect entries event2 and event3, but got %v", entries)
+	}
+	if entries, _ = tlog.ReadFrom(tlog.NextOffset()); len(entries) != 0 {
+		t.Errorf("Expect no entries, but got %v", entries)
+	}
+}
+
+func TestLog_Suspend(t *testing.T) {
+	t.Parallel()
+
+	tlog := NewLog[string](10*time.Second, Config{SuspendPolicy: SuspendExpire})
+	defer tlog.Discard()
+	tlog.Append("entry1")
+
+	// Simulate retention lapsed during a minute of suspend.
+	l := tlog.(*appendLog[string])
+	l.mu.Lock()
+	l.shiftExpirations(l.config.suspendShift(time.Minute))
+	l.mu.Unlock()
+	l.truncate()
+
+	if size := tlog.Size(); size != 0 {
+		t.Errorf("Expect entry truncated, but got size %d", size)
+	}
+}
@@ -0,0 +1,163 @@
+package gocollections
+
+import (
+	"sync"
+	"time"
+)
+
+/*
+Last-Write-Wins Map
+*/
+
+// LWWEntry is replicated element of LWWMap. Timestamp is wall clock time of the write and Node is the node which wrote
+// it. Deleted elements are kept as tombstones until they expire, so the delete is replicated too.
+type LWWEntry[K comparable, V any] struct {
+	Key       K
+	Value     V
+	Timestamp time.Time
+	Node      string
+	ExpiredAt time.Time
+	Deleted   bool
+}
+
+// newer returns true if the entry wins over the other one. Later timestamp wins, ties are resolved by node name.
+func (e LWWEntry[K, V]) newer(other LWWEntry[K, V]) bool {
+	if e.Timestamp.Equal(other.Timestamp) {
+		return e.Node > other.Node
+	}
+	return e.Timestamp.After(other.Timestamp)
+}
+
+// LWWMap is last-write-wins replicated map. Maps of different nodes converge to the same content when they exchange
+// their State by Merge, without a central store. Write with later timestamp wins. It's built on TimeExpiredMap, so
+// it's running goroutine which removes expired elements. To stop this goroutine call Discard() method when this map is
+// not needed any more.
+type LWWMap[K comparable, V any] interface {
+	Add(key K, value V)
+	AddWithDuration(key K, value V, duration time.Duration)
+	Get(key K) (V, error)
+	Del(key K) error
+	Size() int
+	State() []LWWEntry[K, V]
+	Merge(remote []LWWEntry[K, V]) int
+	Discard()
+}
+
+type lwwMap[K comparable, V any] struct {
+	mu       sync.Mutex
+	node     string
+	duration time.Duration
+	entries  TimeExpiredMap[K, LWWEntry[K, V]]
+}
+
+// NewLWWMap creates new LWWMap of the node. Node name must be unique among replicating nodes.
+func NewLWWMap[K comparable, V any](node string, duration time.Duration, configs ...Config) LWWMap[K, V] {
+	return &lwwMap[K, V]{
+		node:     node,
+		duration: duration,
+		entries:  NewTimeExpiredMap[K, LWWEntry[K, V]](duration, configs...),
+	}
+}
+
+// Add adds element to the map with default duration.
+func (m *lwwMap[K, V]) Add(key K, value V) {
+	m.AddWithDuration(key, value, m.duration)
+}
+
+// AddWithDuration adds element to the map with duration.
+func (m *lwwMap[K, V]) AddWithDuration(key K, value V, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now().Round(0)
+	m.write(LWWEntry[K, V]{Key: key, Value: value, Timestamp: now, Node: m.node, ExpiredAt: now.Add(duration)})
+}
+
+// Get returns element by key. It returns KeyError if the key is not in the map or it was deleted.
+func (m *lwwMap[K, V]) Get(key K) (V, error) {
+	var zero V
+	e, err := m.entries.Get(key)
+	if err != nil {
+		return zero, err
+	}
+	if e.Deleted {
+		return zero, &KeyError{Key: key}
+	}
+	return e.Value, nil
+}
+
+// Del deletes element by key. The element is replaced by tombstone, which expires with the element.
+func (m *lwwMap[K, V]) Del(key K) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, err := m.entries.Get(key)
+	if err != nil {
+		return err
+	}
+	if e.Deleted {
+		return &KeyError{Key: key}
+	}
+	var zero V
+	m.write(LWWEntry[K, V]{
+		Key:       key,
+		Value:     zero,
+		Timestamp: time.Now().Round(0),
+		Node:      m.node,
+		ExpiredAt: e.ExpiredAt,
+		Deleted:   true,
+	})
+	return nil
+}
+
+// Size returns number of unexpired and not deleted elements.
+func (m *lwwMap[K, V]) Size() int {
+	count := 0
+	m.entries.Range(func(key K, e LWWEntry[K, V]) bool {
+		if !e.Deleted {
+			count++
+		}
+		return true
+	})
+	return count
+}
+
+// State returns unexpired elements including tombstones for replication to other nodes.
+func (m *lwwMap[K, V]) State() []LWWEntry[K, V] {
+	var result []LWWEntry[K, V]
+	m.entries.Range(func(key K, e LWWEntry[K, V]) bool {
+		result = append(result, e)
+		return true
+	})
+	return result
+}
+
+// Merge applies state of remote node. Remote element replaces local one if it's newer. It returns number of applied
+// elements.
+func (m *lwwMap[K, V]) Merge(remote []LWWEntry[K, V]) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	count := 0
+	for _, e := range remote {
+		if local, err := m.entries.Get(e.Key); err == nil && !e.newer(local) {
+			continue
+		}
+		if m.write(e) {
+			count++
+		}
+	}
+	return count
+}
+
+// write stores unexpired entry. It must be called with locked mutex.
+func (m *lwwMap[K, V]) write(e LWWEntry[K, V]) bool {
+	ttl := time.Until(e.ExpiredAt)
+	if ttl <= 0 {
+		return false
+	}
+	m.entries.AddWithDuration(e.Key, e, ttl)
+	return true
+}
+
+// Discard method stops the goroutine for removing expired elements.
+func (m *lwwMap[K, V]) Discard() {
+	m.entries.Discard()
+}
@@ -0,0 +1,40 @@
+package gocollections
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLWWMap(t *testing.T) {
+	t.Parallel()
+
+	a := NewLWWMap[string, string]("a", 10*time.Second)
+	defer a.Discard()
+	b := NewLWWMap[string, string]("b", 10*time.Second)
+	defer b.Discard()
+
+	a.Add("key1", "a1")
+	a.Add("key2", "a2")
+	time.Sleep(time.Millisecond)
+	b.Add("key1", "b1")
+	_ = a.Del("key2")
+
+	a.Merge(b.State())
+	b.Merge(a.State())
+
+	for _, m := range []LWWMap[string, string]{a, b} {
+		if val, err := m.Get("key1"); err != nil || val != "b1" {
+			t.Errorf("want: %s, got: %s, %v", "b1", val, err)
+		}
+		if _, err := m.Get("key2"); !errors.Is(err, ErrKeyNotFound) {
+			t.Errorf("Expect deleted key2, but got %v", err)
+		}
+		if m.Size() != 1 {
+			t.Errorf("Expect size 1, but got %d", m.Size())
+		}
+	}
+	if n := a.Merge(b.State()); n != 0 {
+		t.Errorf("Expect nothing applied from converged state, but applied %d", n)
+	}
+}
@@ -1,70 +1,385 @@
 package gocollections
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"runtime/pprof"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
-var (
-	ErrKeyNotFound     = errors.New("key not found")
-	ErrIndexOutOfBound = errors.New("index out of bound")
-	ErrExpired         = errors.New("element expired") // When an element is present in the collection but the validity time expires.
-)
+// activeCleaners is number of running goroutines removing expired elements.
+var activeCleaners atomic.Int64
+
+// ActiveCleaners returns number of running goroutines removing expired elements. Every collection runs one until it's
+// stopped or discarded, so it can be used for detection of collections which were not discarded.
+func ActiveCleaners() int64 {
+	return activeCleaners.Load()
+}
 
 type expiredElement[V any] struct {
 	data      V
 	expiredAt time.Time
+	createdAt time.Time     // time when the key was added to the map
+	updatedAt time.Time     // time of the last value change in the map
+	id        uint64        // handle of the list element
+	warned    bool          // true if the element was sent to about to expire channel
+	pos       int           // position of the key in keys of the map
+	vetoed    bool          // true if expiration was vetoed by Config.BeforeEvict
+	ttl       time.Duration // duration of the element, used by Config.SlidingExpiration
 }
 
 // Config struct is for configuration List or Map options.
 type Config struct {
+	// Name of the collection. It's used to distinguish collections in multi collection applications, ex. as pprof label
+	// of the cleaning goroutine.
+	Name string
+	// Labels are additional labels of the collection. They are set as pprof labels of the cleaning goroutine.
+	Labels map[string]string
+	// Register adds the collection to the package registry of live collections. See Registered and DiscardAll.
+	Register bool
 	// CleanJobInterval How often remove expired elements from collections. If it's too often, ex. 1 second and there
 	// is too many elements, than it will cause performance issue.
 	CleanJobInterval time.Duration
 	// Size of expired element channel. If channel is full then last is removed before new is added.
 	ExpiredElChanSize int
+	// Size of replaced element channel of the map. If it's bigger than 0, then old values overwritten by Add or Swap are
+	// sent to this channel. If channel is full then oldest is removed before new is added.
+	ReplacedElChanSize int
+	// MaxLen is maximum length of the list. If it's bigger than 0, then the oldest elements are evicted when the list
+	// is longer. Evicted elements are sent to evicted element channel.
+	MaxLen int
+	// Size of evicted element channel of the list and the map. If channel is full then oldest is removed before new is
+	// added.
+	EvictedElChanSize int
+	// ScreenMode defines what happens with elements caught by ScreenFunc at Add time. Default is ScreenOff.
+	ScreenMode ScreenMode
+	// ScreenFunc returns true if element should be screened. If it's nil, then elements with zero-value key are
+	// screened in map and elements with zero-value value are screened in list. For list the key is nil.
+	ScreenFunc func(key, value any) bool
+	// OnScreened is called with every screened element. It can be used for logging of the warning.
+	OnScreened func(key, value any)
+	// TimeResolution is granularity of expiration times, ex. 100ms or 1s. If it's bigger than 0, then expirations are
+	// rounded up to it and compared with cached clock updated by extra goroutine, which reduces time lookups on hot
+	// paths for the price of precision.
+	TimeResolution time.Duration
+	// WallClock compares expirations by calendar time instead of monotonic clock. By default, elements expire after
+	// their duration of process run time, which is not affected by wall clock changes like NTP steps. With WallClock
+	// elements expire at the calendar time regardless of wall clock jumps or suspend of the process.
+	WallClock bool
+	// AboutToExpireLead is how long before expiration map elements are sent to about to expire channel, ex. to refresh
+	// credentials before they lapse. Elements are checked by the cleaning goroutine, so CleanJobInterval should be
+	// shorter than the lead. Every element is sent once, until it's replaced or its duration is changed.
+	AboutToExpireLead time.Duration
+	// Size of about to expire channel of the map. If channel is full then oldest is removed before new is added.
+	AboutToExpireChanSize int
+	// MinTTL and MaxTTL limit durations of map elements. Durations out of the range are clamped to it, ex. to protect
+	// shared cache from accidental 0-second or 10-year durations. They are not used if they are 0.
+	MinTTL time.Duration
+	MaxTTL time.Duration
+	// RejectTTLOutOfRange rejects elements with duration out of MinTTL and MaxTTL instead of clamping. Rejected
+	// elements are not added and methods returning error return ErrInvalidTTL.
+	RejectTTLOutOfRange bool
+	// GroupFn returns quota group of the map key, ex. tenant by key prefix. If it's set, then the map counts elements
+	// per group, see GroupStats.
+	GroupFn func(key any) string
+	// GroupMaxLen is maximum number of elements in one quota group. If it's bigger than 0 and the group is full, then
+	// adding a new key evicts element of the same group which expires first, so one group can't take the whole map.
+	// Evicted elements are sent to evicted element channel.
+	GroupMaxLen int
+	// ShrinkFactor controls releasing of memory after cleaning. The list storage is reallocated when its capacity is
+	// ShrinkFactor times bigger than its size and the map is rebuilt when ShrinkFactor times more elements were removed
+	// than it has. Smaller factor releases memory sooner for the cost of copying. Default is 4, negative disables it.
+	ShrinkFactor int
+	// BeforeEvict is called by the cleaning goroutine of the map with expired element before it's removed. If it
+	// returns true, then the expiration is vetoed once and the element is extended by EvictGrace, ex. for elements
+	// which are in use and expensive to recompute. The map is locked during the call, so it must not call methods of the
+	// map.
+	BeforeEvict func(key, value any) bool
+	// EvictGrace is extension of the element which expiration was vetoed by BeforeEvict.
+	EvictGrace time.Duration
+	// EarlyExpirationBeta enables probabilistic early expiration of the map elements (XFetch). If it's bigger than 0,
+	// then Get treats element as expired with probability growing as the element gets closer to expiration and
+	// RecomputeCost, so hot elements are recomputed by one caller before they expire for everyone. Value 1 is a good
+	// default, bigger values expire earlier.
+	EarlyExpirationBeta float64
+	// RecomputeCost is estimated duration of recomputing of the element used by EarlyExpirationBeta.
+	RecomputeCost time.Duration
+	// SlidingExpiration extends the map element by its duration whenever it's read by Get or Contains, so elements
+	// expire only when they are not accessed, ex. for sessions.
+	SlidingExpiration bool
+	// TombstoneTTL is how long the map keeps tombstones of keys deleted by SoftDel. If it's 0, then default duration of
+	// the map is used.
+	TombstoneTTL time.Duration
+	// TryLockTimeout is maximum time TryAdd and TryGet wait for the lock of the map. If it's 0, then they fail
+	// immediately when the lock is held.
+	TryLockTimeout time.Duration
+	// SizeWatch configures watchdog of the collection size.
+	SizeWatch SizeWatch
+	// SuspendPolicy defines what happens with elements after suspend of the process. Default is SuspendIgnore.
+	SuspendPolicy SuspendPolicy
+	// SuspendThreshold is minimal time jump which is detected as suspend. Default is 1 second.
+	SuspendThreshold time.Duration
+	// OnSuspend is called by the cleaning goroutine with duration of detected suspend and applied policy.
+	OnSuspend func(suspended time.Duration, policy SuspendPolicy)
+}
+
+// clampTTL returns duration limited by MinTTL and MaxTTL. It returns ErrInvalidTTL if the duration is out of range and
+// RejectTTLOutOfRange is set.
+func (c Config) clampTTL(d time.Duration) (time.Duration, error) {
+	switch {
+	case c.MinTTL > 0 && d < c.MinTTL:
+		if c.RejectTTLOutOfRange {
+			return d, ErrInvalidTTL
+		}
+		return c.MinTTL, nil
+	case c.MaxTTL > 0 && d > c.MaxTTL:
+		if c.RejectTTLOutOfRange {
+			return d, ErrInvalidTTL
+		}
+		return c.MaxTTL, nil
+	}
+	return d, nil
+}
+
+// ScreenMode defines how collections handle elements caught by Config.ScreenFunc.
+type ScreenMode int
+
+const (
+	ScreenOff    ScreenMode = iota // Elements are not screened.
+	ScreenWarn                     // Screened element is added and Config.OnScreened is called.
+	ScreenReject                   // Screened element is not added and Config.OnScreened is called.
+)
+
+// screened checks element against screen configuration. It returns true if element must not be added. Parameter zero
+// is used when ScreenFunc is not defined.
+func (c Config) screened(key, value any, zero bool) bool {
+	caught := zero
+	if c.ScreenFunc != nil {
+		caught = c.ScreenFunc(key, value)
+	}
+	if !caught {
+		return false
+	}
+	if c.OnScreened != nil {
+		c.OnScreened(key, value)
+	}
+	return c.ScreenMode == ScreenReject
+}
+
+// newConfig returns the first config or default config if configs are empty. Unset fields are set to default values.
+func newConfig(configs []Config) Config {
+	var config Config
+	if len(configs) > 0 {
+		config = configs[0]
+	}
+	if config.CleanJobInterval <= 0 {
+		config.CleanJobInterval = 60 * time.Second
+	}
+	return config
+}
+
+// startCleaner runs goroutine for removing expired elements, which calls clean every Config.CleanJobInterval until
+// quitChan is closed.
+func startCleaner(config Config, quitChan chan struct{}, clean func()) {
+	activeCleaners.Add(1)
+	go func() {
+		defer activeCleaners.Add(-1)
+		config.labelGoroutine()
+		ticker := time.NewTicker(config.CleanJobInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				clean()
+			case <-quitChan:
+				return
+			}
+		}
+	}()
+}
+
+// labelGoroutine sets pprof labels with collection name and labels to the current goroutine.
+func (c Config) labelGoroutine() {
+	if c.Name == "" && len(c.Labels) == 0 {
+		return
+	}
+	var args []string
+	if c.Name != "" {
+		args = append(args, "collection", c.Name)
+	}
+	for key, val := range c.Labels {
+		args = append(args, key, val)
+	}
+	pprof.SetGoroutineLabels(pprof.WithLabels(context.Background(), pprof.Labels(args...)))
+}
+
+// sendDropOldest sends value to the buffered channel. If the channel is full, then the oldest value is removed.
+func sendDropOldest[V any](ch chan V, value V) {
+	if cap(ch) == 0 {
+		return
+	}
+	for {
+		select {
+		case ch <- value:
+			return
+		default:
+		}
+		select {
+		case <-ch:
+		default:
+		}
+	}
+}
+
+// isZero returns true if value is zero value of its type.
+func isZero[V any](value V) bool {
+	return reflect.ValueOf(&value).Elem().IsZero()
+}
+
+// NoExpiry is duration of elements which never expire. It can be used as default duration of the collection or as
+// duration of the element.
+const NoExpiry = time.Duration(math.MaxInt64)
+
+// noExpiryTime is expiration time of elements which never expire.
+var noExpiryTime = time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+// remaining returns remaining duration from now until expiredAt. It's NoExpiry for elements which never expire.
+func remaining(expiredAt, now time.Time) time.Duration {
+	if expiredAt.Equal(noExpiryTime) {
+		return NoExpiry
+	}
+	return expiredAt.Sub(now)
+}
+
+// waitPollInterval is how often wait helpers like Drain and WaitForEmpty check the collection.
+const waitPollInterval = 10 * time.Millisecond
+
+// waitUntil calls done function periodically until it returns true, error or context ends.
+func waitUntil(ctx context.Context, done func() (bool, error)) error {
+	ticker := time.NewTicker(waitPollInterval)
+	defer ticker.Stop()
+
+	for {
+		ok, err := done()
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Closer controls lifecycle of the collection and its goroutine for removing expired elements.
+type Closer interface {
+	Discard()
+	Stop()
+	Start() error
+	IsClosed() bool
+	Drain(ctx context.Context) error
+	WaitForEmpty(ctx context.Context) error
+}
+
+// Expirer provides elements removed from the collection because they expired.
+type Expirer[V any] interface {
+	ExpiredElChan() chan V
 }
 
 /*
 Time Expired List
 */
 
-// TimeExpiredList is a list collection which values expires in time.
-type TimeExpiredList[V any] interface {
-	Add(value V)
+// ListReader is the read part of TimeExpiredList.
+type ListReader[V any] interface {
 	Get(index int) (V, error)
 	GetAll() []V
+	Range(fn func(value V) bool)
+	GetAllEntries() []ListEntry[V]
+	GetAllReversed() []V
+	GetAllRaw() []ListEntry[V]
+	GetByHandle(h Handle) (V, error)
+	Last() (V, error)
+	Iterate(dir Direction, fn func(value V) bool)
+	Size() int
+	SizeRaw() int
+}
+
+// ListWriter is the write part of TimeExpiredList.
+type ListWriter[V any] interface {
+	Add(value V)
+	AddWithHandle(value V) Handle
 	Del(i int) error
+	DelByHandle(h Handle) error
 	Clear()
-	Discard()
-	Size() int
-	ExpiredElChan() chan V
 }
 
+// TimeExpiredList is a list collection which values expires in time.
+type TimeExpiredList[V any] interface {
+	ListReader[V]
+	ListWriter[V]
+	Expirer[V]
+	Closer
+	Name() string
+	EvictedElChan() chan V
+}
+
+// Direction is direction of the list iteration.
+type Direction int
+
+const (
+	Forward  Direction = iota // From the oldest to the most recent element.
+	Backward                  // From the most recent to the oldest element.
+)
+
+// ListEntry is an element of the list with its handle and expiration time.
+type ListEntry[V any] struct {
+	Handle    Handle
+	Value     V
+	ExpiredAt time.Time
+}
+
+// TTL returns remaining time until the element expires. It's NoExpiry if the element never expires.
+func (e ListEntry[V]) TTL() time.Duration {
+	return remaining(e.ExpiredAt, time.Now())
+}
+
+// Handle is an opaque reference to the list element. It remains valid while the element is in the list, even if other
+// elements are removed.
+type Handle uint64
+
 type timeExpiredList[V any] struct {
 	config      Config
 	mu          sync.Mutex
 	duration    time.Duration
 	data        []expiredElement[V]
+	lastID      uint64 // id of the last added element, ids are increasing with list order
 	dataString  []V
 	expiredChan chan V
+	evictedChan chan V            // channel for elements evicted because of MaxLen
+	duplicate   func(a, b V) bool // equality of elements in deduplicating list, nil otherwise
 	quitChan    chan struct{}
+	clock       *coarseClock    // cached clock if Config.TimeResolution is set
+	suspend     suspendDetector // detects suspend of the process between cleaning runs
+	sizeWatch   sizeWatcher     // checks size by Config.SizeWatch
+	stopped     bool            // true after Stop
+	closed      bool            // true after Discard
 }
 
 // NewTimeExpiredList creates instance of TimeExpiredList interface. It runs goroutine for removing expired elements.
 func NewTimeExpiredList[V any](duration time.Duration, configs ...Config) TimeExpiredList[V] {
-	var config Config
-	if len(configs) < 1 {
-		// Default config if not provided
-		config = Config{
-			CleanJobInterval:  60 * time.Second,
-			ExpiredElChanSize: 0,
-		}
-	} else {
-		// Or use provided configuration
-		config = configs[0]
-	}
+	config := newConfig(configs)
 
 	tlist := &timeExpiredList[V]{
 		config:      config,
@@ -72,20 +387,131 @@ func NewTimeExpiredList[V any](duration time.Duration, configs ...Config) TimeEx
 		data:        []expiredElement[V]{},
 		dataString:  []V{},
 		expiredChan: make(chan V, config.ExpiredElChanSize),
+		evictedChan: make(chan V, config.EvictedElChanSize),
 		quitChan:    make(chan struct{}),
+		clock:       newCoarseClock(config),
 	}
 
 	// Run goroutine for removing expired elements.
-	go tlist.run()
+	startCleaner(tlist.config, tlist.quitChan, tlist.removeExpired)
 
+	if config.Register {
+		register(tlist, config)
+	}
+
+	return tlist
+}
+
+// NewDedupTimeExpiredList creates TimeExpiredList which doesn't contain duplicates. Add removes an element with the same
+// key as the new element from the list before the new element is appended, so the element is refreshed instead of
+// duplicated. Handle of the removed element is no longer valid.
+func NewDedupTimeExpiredList[V any, K comparable](duration time.Duration, key func(value V) K,
+	configs ...Config) TimeExpiredList[V] {
+	tlist := NewTimeExpiredList[V](duration, configs...).(*timeExpiredList[V])
+	tlist.duplicate = func(a, b V) bool {
+		return key(a) == key(b)
+	}
 	return tlist
 }
 
 // Add method add element to TimeExpiredList
 func (l *timeExpiredList[V]) Add(value V) {
+	l.AddWithHandle(value)
+}
+
+// AddWithHandle adds element to the list and returns its handle. Handle is 0 if the element was not added.
+func (l *timeExpiredList[V]) AddWithHandle(value V) Handle {
+	if l.config.ScreenMode != ScreenOff && l.config.screened(nil, value, isZero(value)) {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return 0
+	}
+	if l.duplicate != nil {
+		l.removeDuplicate(value)
+	}
+	l.lastID++
+	l.data = append(l.data, expiredElement[V]{expiredAt: l.clock.expiry(l.duration), data: value, id: l.lastID})
+	if l.config.MaxLen > 0 && len(l.data) > l.config.MaxLen {
+		l.evictOldest(len(l.data) - l.config.MaxLen)
+	}
+	return Handle(l.lastID)
+}
+
+// removeDuplicate removes element with the same key as value. It must be called with locked mutex.
+func (l *timeExpiredList[V]) removeDuplicate(value V) {
+	for i, e := range l.data {
+		if l.duplicate(e.data, value) {
+			l.deleteAt(i)
+			return
+		}
+	}
+}
+
+// deleteAt removes element at index i. Vacated slot is cleared, so the removed value is not reachable from the backing
+// array. It must be called with locked mutex.
+func (l *timeExpiredList[V]) deleteAt(i int) {
+	oldLen := len(l.data)
+	l.data = compact(append(l.data[:i], l.data[i+1:]...), oldLen, 0)
+}
+
+// GetByHandle returns element by handle returned from AddWithHandle.
+func (l *timeExpiredList[V]) GetByHandle(h Handle) (V, error) {
+	var result V
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	l.data = append(l.data, expiredElement[V]{expiredAt: time.Now().Add(l.duration), data: value})
+	if l.closed {
+		return result, ErrClosed
+	}
+	i, found := l.indexOf(h)
+	if !found {
+		return result, &KeyError{Key: h}
+	}
+	if l.data[i].expiredAt.Before(l.clock.Now()) {
+		return result, &ExpiredError{Key: h, ExpiredAt: l.data[i].expiredAt}
+	}
+	return l.data[i].data, nil
+}
+
+// DelByHandle removes element by handle returned from AddWithHandle.
+func (l *timeExpiredList[V]) DelByHandle(h Handle) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return ErrClosed
+	}
+	i, found := l.indexOf(h)
+	if !found {
+		return &KeyError{Key: h}
+	}
+	l.deleteAt(i)
+	return nil
+}
+
+// indexOf returns index of the element with handle. Elements are ordered by id, so binary search is used. It must be
+// called with locked mutex.
+func (l *timeExpiredList[V]) indexOf(h Handle) (int, bool) {
+	id := uint64(h)
+	i := sort.Search(len(l.data), func(i int) bool {
+		return l.data[i].id >= id
+	})
+	return i, i < len(l.data) && l.data[i].id == id
+}
+
+// evictOldest removes n oldest elements from the list. Already expired elements are sent to expired element channel,
+// others to evicted element channel. It must be called with locked mutex.
+func (l *timeExpiredList[V]) evictOldest(n int) {
+	for _, e := range l.data[:n] {
+		if e.expiredAt.After(l.clock.Now()) {
+			sendDropOldest(l.evictedChan, e.data)
+		} else {
+			sendDropOldest(l.expiredChan, e.data)
+		}
+	}
+	kept := copy(l.data, l.data[n:])
+	l.data = compact(l.data[:kept], len(l.data), l.config.shrinkFactor())
 }
 
 // Get returns element by index.
@@ -93,11 +519,14 @@ func (l *timeExpiredList[V]) Get(i int) (V, error) {
 	var result V
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	if i < 0 && i >= len(l.data) {
-		return result, ErrIndexOutOfBound
+	if l.closed {
+		return result, ErrClosed
+	}
+	if i < 0 || i >= len(l.data) {
+		return result, &IndexError{Index: i, Len: len(l.data)}
 	}
-	if l.data[i].expiredAt.Before(time.Now()) {
-		return result, ErrExpired
+	if l.data[i].expiredAt.Before(l.clock.Now()) {
+		return result, &ExpiredError{Key: i, ExpiredAt: l.data[i].expiredAt}
 	}
 	result = l.data[i].data
 	return result, nil
@@ -109,7 +538,7 @@ func (l *timeExpiredList[V]) GetAll() []V {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	for _, v := range l.data {
-		if v.expiredAt.Before(time.Now()) {
+		if v.expiredAt.Before(l.clock.Now()) {
 			// skip element if expired.
 			continue
 		}
@@ -118,75 +547,281 @@ func (l *timeExpiredList[V]) GetAll() []V {
 	return result
 }
 
-// Del removes element by index.
-func (l *timeExpiredList[V]) Del(i int) error {
-	if i < 0 || i >= len(l.data) {
-		return ErrIndexOutOfBound
+// Range calls fn for every unexpired element from the oldest until fn returns false. Unlike GetAll it doesn't copy the
+// elements. The list is locked during iteration, so fn must not call methods of the list.
+func (l *timeExpiredList[V]) Range(fn func(value V) bool) {
+	l.Iterate(Forward, fn)
+}
+
+// GetAllReversed returns TimeExpiredElements values in slice, the most recent first.
+func (l *timeExpiredList[V]) GetAllReversed() []V {
+	var result []V
+	l.Iterate(Backward, func(value V) bool {
+		result = append(result, value)
+		return true
+	})
+	return result
+}
+
+// Last returns the most recent unexpired element. It returns IndexError if the list is empty and ExpiredError of the
+// most recent element if all elements expired.
+func (l *timeExpiredList[V]) Last() (V, error) {
+	var result V
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return result, ErrClosed
+	}
+	if len(l.data) == 0 {
+		return result, &IndexError{Index: -1, Len: 0}
+	}
+	now := l.clock.Now()
+	for i := len(l.data) - 1; i >= 0; i-- {
+		if l.data[i].expiredAt.After(now) {
+			return l.data[i].data, nil
+		}
+	}
+	last := len(l.data) - 1
+	return result, &ExpiredError{Key: last, ExpiredAt: l.data[last].expiredAt}
+}
+
+// Iterate calls fn for every unexpired element in direction dir until fn returns false. The list is locked during
+// iteration, so fn must not call methods of the list.
+func (l *timeExpiredList[V]) Iterate(dir Direction, fn func(value V) bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.clock.Now()
+	for n := 0; n < len(l.data); n++ {
+		i := n
+		if dir == Backward {
+			i = len(l.data) - 1 - n
+		}
+		if l.data[i].expiredAt.Before(now) {
+			continue
+		}
+		if !fn(l.data[i].data) {
+			return
+		}
+	}
+}
+
+// GetAllEntries returns unexpired elements with their handles and expiration times.
+func (l *timeExpiredList[V]) GetAllEntries() []ListEntry[V] {
+	var result []ListEntry[V]
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, e := range l.data {
+		if e.expiredAt.Before(l.clock.Now()) {
+			// skip element if expired.
+			continue
+		}
+		result = append(result, ListEntry[V]{Handle: Handle(e.id), Value: e.data, ExpiredAt: e.expiredAt})
 	}
+	return result
+}
 
+// Del removes element by index.
+func (l *timeExpiredList[V]) Del(i int) error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	l.data = append(l.data[:i], l.data[i+1:]...)
+	if l.closed {
+		return ErrClosed
+	}
+	if i < 0 || i >= len(l.data) {
+		return &IndexError{Index: i, Len: len(l.data)}
+	}
+	l.deleteAt(i)
 	return nil
 }
 
-// Size returns size of the list
+// Size returns number of unexpired elements of the list. All elements of the list have the same duration, so they
+// expire in order of adding and Size finds the first unexpired element by binary search. Wall clock can go back, so the
+// list with Config.WallClock is iterated.
 func (l *timeExpiredList[V]) Size() int {
-	var count = 0
 	l.mu.Lock()
 	defer l.mu.Unlock()
+	now := l.clock.Now()
+	if !l.config.WallClock {
+		return len(l.data) - sort.Search(len(l.data), func(i int) bool {
+			return l.data[i].expiredAt.After(now)
+		})
+	}
+	var count = 0
 	for _, e := range l.data {
 		// Don't count if element already expired.
-		if e.expiredAt.After(time.Now()) {
+		if e.expiredAt.After(now) {
 			count++
 		}
 	}
 	return count
 }
 
+// SizeRaw returns size of the list including expired elements which are not removed yet. It's for debugging of the
+// memory usage, ex. to distinguish logically removed elements from not yet reclaimed ones.
+func (l *timeExpiredList[V]) SizeRaw() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.data)
+}
+
+// GetAllRaw returns all elements including expired ones which are not removed yet.
+func (l *timeExpiredList[V]) GetAllRaw() []ListEntry[V] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	result := make([]ListEntry[V], 0, len(l.data))
+	for _, e := range l.data {
+		result = append(result, ListEntry[V]{Handle: Handle(e.id), Value: e.data, ExpiredAt: e.expiredAt})
+	}
+	return result
+}
+
 // Clear method clears all elements from the list.
 func (l *timeExpiredList[V]) Clear() {
 	l.mu.Lock()
-	l.mu.Unlock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return
+	}
 	l.data = []expiredElement[V]{}
 }
 
-// Discard method stops the goroutine for removing elements and discards data in internal slice.
+// Discard method stops the goroutine for removing elements and discards data in internal slice. After Discard the list
+// is closed, methods returning error return ErrClosed and other methods do nothing.
 func (l *timeExpiredList[V]) Discard() {
-	l.quitChan <- struct{}{}
 	l.mu.Lock()
 	defer l.mu.Unlock()
+	if l.closed {
+		return
+	}
+	if !l.stopped {
+		close(l.quitChan)
+	}
+	l.closed = true
+	l.clock.stop()
+	if l.config.Register {
+		unregister(l)
+	}
 	l.data = nil
 }
 
+// Stop method stops the goroutine for removing elements. Data in the list are retained and expired elements stay in
+// the list until Start is called.
+func (l *timeExpiredList[V]) Stop() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed || l.stopped {
+		return
+	}
+	l.stopped = true
+	close(l.quitChan)
+}
+
+// Start method starts again the goroutine for removing elements stopped by Stop. It returns ErrClosed if the list was
+// discarded.
+func (l *timeExpiredList[V]) Start() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return ErrClosed
+	}
+	if !l.stopped {
+		return nil
+	}
+	l.stopped = false
+	l.quitChan = make(chan struct{})
+	startCleaner(l.config, l.quitChan, l.removeExpired)
+	return nil
+}
+
+// Drain waits until all elements present in the list at the time of the call expire or are deleted. Expired elements
+// are removed and sent to expired element channel the same way as by the cleaning goroutine. It returns context error if
+// the context ends before.
+func (l *timeExpiredList[V]) Drain(ctx context.Context) error {
+	var latest time.Time
+	l.mu.Lock()
+	for _, e := range l.data {
+		if e.expiredAt.After(latest) {
+			latest = e.expiredAt
+		}
+	}
+	l.mu.Unlock()
+
+	return waitUntil(ctx, func() (bool, error) {
+		if l.IsClosed() {
+			return false, ErrClosed
+		}
+		l.removeExpired()
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		for _, e := range l.data {
+			if !e.expiredAt.After(latest) {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+}
+
+// WaitForEmpty waits until the list has no unexpired element. It returns context error if the context ends before.
+func (l *timeExpiredList[V]) WaitForEmpty(ctx context.Context) error {
+	return waitUntil(ctx, func() (bool, error) {
+		if l.IsClosed() {
+			return false, ErrClosed
+		}
+		l.removeExpired()
+		return l.Size() == 0, nil
+	})
+}
+
+// Name returns name of the list set in Config.
+func (l *timeExpiredList[V]) Name() string {
+	return l.config.Name
+}
+
+// IsClosed returns true if the list was discarded.
+func (l *timeExpiredList[V]) IsClosed() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.closed
+}
+
 func (l *timeExpiredList[V]) ExpiredElChan() chan V {
 	return l.expiredChan
 }
 
-// run method runs the goroutine for removing expired elements.
-func (l *timeExpiredList[V]) run() {
-	ticker := time.NewTicker(l.config.CleanJobInterval)
-	defer ticker.Stop()
+// EvictedElChan returns channel with elements evicted because of Config.MaxLen. It's used only if
+// Config.EvictedElChanSize is bigger than 0.
+func (l *timeExpiredList[V]) EvictedElChan() chan V {
+	return l.evictedChan
+}
 
-	for {
-		select {
-		case <-ticker.C:
-			l.removeExpired()
-		case <-l.quitChan:
-			return
-		}
+// shiftExpirations moves expirations of all elements by d. It must be called with locked mutex.
+func (l *timeExpiredList[V]) shiftExpirations(d time.Duration) {
+	for i := range l.data {
+		l.data[i].expiredAt = shiftExpiry(l.data[i].expiredAt, d)
 	}
 }
 
+// checkSize checks size of the list by Config.SizeWatch.
+func (l *timeExpiredList[V]) checkSize() {
+	l.mu.Lock()
+	alerts := l.sizeWatch.check(l.config, len(l.data))
+	l.mu.Unlock()
+	l.config.SizeWatch.notify(alerts)
+}
+
 // removeExpired method removes expired elements in list.
 func (l *timeExpiredList[V]) removeExpired() {
-	var newData []expiredElement[V]
+	l.suspend.handle(l.config, &l.mu, l.shiftExpirations)
+	defer l.checkSize()
 	l.mu.Lock()
 	defer l.mu.Unlock()
+	// Unexpired elements are compacted in place to reuse the storage.
+	newData := l.data[:0]
 	for _, val := range l.data {
-		if val.expiredAt.After(time.Now()) {
+		if val.expiredAt.After(l.clock.Now()) {
 			// If Element is not expired then add to new data slice.
-			newData = append(newData, expiredElement[V]{data: val.data, expiredAt: val.expiredAt})
+			newData = append(newData, val)
 		} else {
 			// If expired element channel is defined and size is bigger than 0, than send expired element to this channel.
 			if cap(l.expiredChan) > 0 {
@@ -199,181 +834,1090 @@ func (l *timeExpiredList[V]) removeExpired() {
 			}
 		}
 	}
-	l.data = newData
+	l.data = compact(newData, len(l.data), l.config.shrinkFactor())
 }
 
 /*
 Time Expired Map
 */
 
-// TimeExpiredMap implementation of this interface is a map with elements which are expire base on expiration duration.
-// Implementation of this map is running goroutine which removes expired element. To stop this goroutine call Discard()
-// method when this map is not needed any more.
-type TimeExpiredMap[K comparable, V any] interface {
-	Add(key K, object V)
-	AddWithDuration(key K, data V, duration time.Duration)
+// MapReader is the read part of TimeExpiredMap.
+type MapReader[K comparable, V any] interface {
 	Get(key K) (V, error)
-	Del(key K) error
+	TryGet(key K) (V, error)
+	GetManyConsistent(keys []K) map[K]V
+	GetEntry(key K) (Entry[K, V], error)
+	GetValidFor(key K, minRemaining time.Duration) (V, error)
+	GetAllEntries() []Entry[K, V]
+	Keys() []K
+	Values() []V
+	Range(fn func(key K, value V) bool)
+	IterateByExpiry(order SortOrder, fn func(e Entry[K, V]) bool)
+	GetAllRaw() []Entry[K, V]
+	SampleKeys(n int) []K
+	SampleEntries(n int) []Entry[K, V]
+	Cursor() *MapCursor[K, V]
 	Contains(key K) bool
+	IsDeleted(key K) bool
 	Size() int
-	Clear()
-	Discard()
-	ExpiredElChan() chan V
+	SizeRaw() int
+	WaitFor(ctx context.Context, key K) (V, error)
 }
 
-type timeExpiredMap[K comparable, V any] struct {
-	config      Config
-	mu          sync.Mutex
-	duration    time.Duration           // default element duration
-	data        map[K]expiredElement[V] // map of elements
-	expiredChan chan V
-	quitChan    chan struct{} // channel for indicating to end goroutines for removing expired elements
+// MapWriter is the write part of TimeExpiredMap.
+type MapWriter[K comparable, V any] interface {
+	Add(key K, object V)
+	TryAdd(key K, data V) error
+	AddWithDuration(key K, data V, duration time.Duration)
+	AddWithContext(ctx context.Context, key K, data V)
+	AddWithRefresher(key K, data V, duration time.Duration, refresh func(key K, value V) (V, error))
+	AddWithCallback(key K, data V, fn func(key K, value V))
+	AddAll(entries []Entry[K, V]) []error
+	Swap(key K, data V) (old V, existed bool)
+	SetTTL(key K, duration time.Duration) error
+	Persist(key K) error
+	Del(key K) error
+	GetDel(key K) (V, error)
+	SoftDel(key K) error
+	AddUnlessDeleted(key K, data V) error
+	GetOrCompute(key K, fn func() (V, error)) (V, error)
+	Clear()
+}
+
+// TimeExpiredMap implementation of this interface is a map with elements which are expire base on expiration duration.
+// Implementation of this map is running goroutine which removes expired element. To stop this goroutine call Discard()
+// method when this map is not needed any more.
+type TimeExpiredMap[K comparable, V any] interface {
+	MapReader[K, V]
+	MapWriter[K, V]
+	Expirer[V]
+	Closer
+	Name() string
+	ReplacedElChan() chan V
+	EvictedElChan() chan V
+	AboutToExpireChan() chan Entry[K, V]
+	WatchWhere(pred func(key K, value V) bool, bufferSize int) Watch[K, V]
+	GroupStats() map[string]GroupStats
+}
+
+// Entry is an element of the map with key, value and duration used for bulk operations. If TTL is 0, then default
+// duration of the map is used and if it's NoExpiry, then the element never expires. Entries returned from the map have
+// remaining duration in TTL, time when the key was added in CreatedAt and time of the last value change in UpdatedAt.
+type Entry[K comparable, V any] struct {
+	Key       K
+	Value     V
+	TTL       time.Duration
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type timeExpiredMap[K comparable, V any] struct {
+	config       Config
+	mu           sync.Mutex
+	duration     time.Duration           // default element duration
+	data         map[K]expiredElement[V] // map of elements
+	keys         []K                     // keys of data in random access order, used for sampling
+	expiredCount int                     // number of elements which were expired at countedAt
+	countedAt    time.Time               // time of the last count of expired elements
+	nextExpiry   time.Time               // Size counts expired elements again at this time
+	waiters      map[K]*keyWaiters[V]    // goroutines waiting in WaitFor for the key
+	expiredChan  chan V
+	replacedChan chan V                    // channel for old values overwritten by Add or Swap
+	evictedChan  chan V                    // channel for elements evicted because of Config.GroupMaxLen
+	aboutChan    chan Entry[K, V]          // channel for elements which expire within Config.AboutToExpireLead
+	quitChan     chan struct{}             // channel for indicating to end goroutines for removing expired elements
+	clock        *coarseClock              // cached clock if Config.TimeResolution is set
+	lastID       uint64                    // id of the last added element, used to recognize replaced elements
+	discardChan  chan struct{}             // channel closed by Discard, it ends goroutines of AddWithContext
+	suspend      suspendDetector           // detects suspend of the process between cleaning runs
+	sizeWatch    sizeWatcher               // checks size by Config.SizeWatch
+	groups       map[string]GroupStats     // statistics of quota groups by Config.GroupFn
+	groupKeys    map[string]map[K]struct{} // keys of quota groups by Config.GroupFn
+	watches      map[*watch[K, V]]struct{} // watches created by WatchWhere
+	removed      int                       // number of removed elements since the last rebuild of data
+	refreshers   map[K]*refresher[K, V]    // refreshers of elements added by AddWithRefresher
+	computes     map[K]*memoCall[V]        // running computations of GetOrCompute
+	callbacks    map[K]func(K, V)          // expiration callbacks of elements added by AddWithCallback
+	contexts     map[K]chan struct{}       // channels of elements added by AddWithContext, closed when they are removed
+	tombstones   map[K]time.Time           // expirations of tombstones of keys deleted by SoftDel
+	validator    func(K, V) bool           // validator of NewValidatedTimeExpiredMap, nil otherwise
+	stopped      bool                      // true after Stop
+	closed       bool                      // true after Discard
 }
 
 // NewTimeExpiredMap creates new TimeExpiredMap object.
 func NewTimeExpiredMap[K comparable, V any](duration time.Duration, configs ...Config) TimeExpiredMap[K, V] {
-	var config Config
+	config := newConfig(configs)
 	if len(configs) < 1 {
-		// Default config if not provided
-		config = Config{
-			CleanJobInterval:  60 * time.Second,
-			ExpiredElChanSize: 100,
-		}
-	} else {
-		// Or use provided configuration
-		config = configs[0]
+		// Map buffers expired elements by default.
+		config.ExpiredElChanSize = 100
 	}
 
 	tmap := &timeExpiredMap[K, V]{
-		config:      config,
-		duration:    duration,
-		data:        make(map[K]expiredElement[V]),
-		expiredChan: make(chan V, config.ExpiredElChanSize),
-		quitChan:    make(chan struct{}),
+		config:       config,
+		duration:     duration,
+		data:         make(map[K]expiredElement[V]),
+		expiredChan:  make(chan V, config.ExpiredElChanSize),
+		replacedChan: make(chan V, config.ReplacedElChanSize),
+		evictedChan:  make(chan V, config.EvictedElChanSize),
+		aboutChan:    make(chan Entry[K, V], config.AboutToExpireChanSize),
+		quitChan:     make(chan struct{}),
+		clock:        newCoarseClock(config),
+		discardChan:  make(chan struct{}),
+		groups:       make(map[string]GroupStats),
+		watches:      make(map[*watch[K, V]]struct{}),
+	}
+
+	startCleaner(tmap.config, tmap.quitChan, tmap.removeExpired)
+
+	if config.Register {
+		register(tmap, config)
 	}
 
-	go tmap.run()
+	return tmap
+}
 
+// NewValidatedTimeExpiredMap creates TimeExpiredMap with validator consulted by Get. If the validator returns false,
+// then the element is treated as expired and it's removed from the map, ex. cached connection which turns out to be
+// broken.
+func NewValidatedTimeExpiredMap[K comparable, V any](duration time.Duration, valid func(key K, value V) bool,
+	configs ...Config) TimeExpiredMap[K, V] {
+	tmap := NewTimeExpiredMap[K, V](duration, configs...).(*timeExpiredMap[K, V])
+	tmap.validator = valid
 	return tmap
 }
 
 // Add method adds element to the map with key.
 func (m *timeExpiredMap[K, V]) Add(key K, data V) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	m.data[key] = expiredElement[V]{expiredAt: time.Now().Add(m.duration), data: data}
+	m.AddWithDuration(key, data, m.duration)
 }
 
 // AddWithDuration adds element to the map with key. It will set custom duration time of the element in the internal map.
 func (m *timeExpiredMap[K, V]) AddWithDuration(key K, data V, duration time.Duration) {
+	m.add(key, data, duration)
+}
+
+// Swap adds element to the map with key and returns previous unexpired value of the key. Flag existed is false if the
+// key was not in the map or the element expired.
+func (m *timeExpiredMap[K, V]) Swap(key K, data V) (old V, existed bool) {
+	return m.add(key, data, m.duration)
+}
+
+// AddAll adds all entries to the map under one lock. It returns nil if all entries were added. Otherwise, it returns
+// slice of errors of the same length as entries, with nil on positions of added entries.
+func (m *timeExpiredMap[K, V]) AddAll(entries []Entry[K, V]) []error {
+	var errs []error
+	setErr := func(i int, err error) {
+		if errs == nil {
+			errs = make([]error, len(entries))
+		}
+		errs[i] = err
+	}
+
+	// Validate entries before lock, because screen callbacks are called.
+	valid := make([]bool, len(entries))
+	ttls := make([]time.Duration, len(entries))
+	for i, e := range entries {
+		if e.TTL < 0 {
+			setErr(i, ErrInvalidTTL)
+			continue
+		}
+		ttl := e.TTL
+		if ttl == 0 {
+			ttl = m.duration
+		}
+		ttl, err := m.screenAdd(e.Key, e.Value, ttl)
+		if err != nil {
+			setErr(i, err)
+			continue
+		}
+		valid[i] = true
+		ttls[i] = ttl
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, e := range entries {
+		if !valid[i] {
+			continue
+		}
+		if m.closed {
+			setErr(i, ErrClosed)
+			continue
+		}
+		m.set(e.Key, e.Value, ttls[i])
+	}
+	return errs
+}
+
+// add adds element to the map with key and duration and returns previous unexpired value of the key. Replaced value
+// is sent to replaced element channel.
+func (m *timeExpiredMap[K, V]) add(key K, data V, duration time.Duration) (old V, existed bool) {
+	duration, err := m.prepareAdd(key, data, duration)
+	if err != nil {
+		return old, false
+	}
+	defer m.mu.Unlock()
+	return m.set(key, data, duration)
+}
+
+// screenAdd screens the element and limits its duration by Config.MinTTL and Config.MaxTTL. It returns ErrRejected if
+// the element was screened and ErrInvalidTTL if the duration is out of range. It's called before the mutex is locked,
+// because screen callbacks are called.
+func (m *timeExpiredMap[K, V]) screenAdd(key K, data V, duration time.Duration) (time.Duration, error) {
+	if m.config.ScreenMode != ScreenOff {
+		var zero K
+		if m.config.screened(key, data, key == zero) {
+			return 0, ErrRejected
+		}
+	}
+	return m.config.clampTTL(duration)
+}
+
+// prepareAdd screens the element like screenAdd and locks the mutex. It returns errors of screenAdd or ErrClosed after
+// Discard. The mutex is locked only if it returns nil error.
+func (m *timeExpiredMap[K, V]) prepareAdd(key K, data V, duration time.Duration) (time.Duration, error) {
+	duration, err := m.screenAdd(key, data, duration)
+	if err != nil {
+		return 0, err
+	}
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return 0, ErrClosed
+	}
+	return duration, nil
+}
+
+// AddWithContext adds element to the map with key. When the context ends, the element is removed and sent to expired
+// element channel, as if it expired. It runs goroutine which ends when the context ends, the element is removed or
+// replaced, or the map is discarded.
+func (m *timeExpiredMap[K, V]) AddWithContext(ctx context.Context, key K, data V) {
+	duration, err := m.prepareAdd(key, data, m.duration)
+	if err != nil {
+		return
+	}
+	defer m.mu.Unlock()
+	m.set(key, data, duration)
+	e := m.data[key]
+	removed := make(chan struct{})
+	if m.contexts == nil {
+		m.contexts = make(map[K]chan struct{})
+	}
+	m.contexts[key] = removed
+	go m.expireOnDone(ctx, key, e.id, removed)
+}
+
+// expireOnDone removes element with the id when the context ends. The goroutine waits until the element is removed,
+// not until it expires, because its expiration can be extended, ex. by SetTTL.
+func (m *timeExpiredMap[K, V]) expireOnDone(ctx context.Context, key K, id uint64, removed chan struct{}) {
+	select {
+	case <-ctx.Done():
+	case <-removed:
+		return
+	case <-m.discardChan:
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e, found := m.data[key]; found && e.id == id {
+		m.expire(key, e)
+	}
+}
+
+// set stores element in the map, notifies waiters and returns previous unexpired value. It must be called with
+// locked mutex.
+func (m *timeExpiredMap[K, V]) set(key K, data V, duration time.Duration) (old V, existed bool) {
+	// Eviction from the quota group removes another element and moves keys, so it runs before the position is taken.
+	m.groupEvict(key)
+	now := m.clock.Now()
+	createdAt := now
+	e, found := m.data[key]
+	if found && e.expiredAt.After(now) {
+		old, existed = e.data, true
+		createdAt = e.createdAt
+		sendDropOldest(m.replacedChan, old)
+	}
+	pos := e.pos
+	delete(m.refreshers, key)
+	delete(m.callbacks, key)
+	delete(m.tombstones, key)
+	m.releaseContext(key)
+	if found {
+		m.uncount(e)
+	} else {
+		pos = len(m.keys)
+		m.keys = append(m.keys, key)
+	}
+	m.lastID++
+	expiredAt := m.clock.expiry(duration)
+	m.lowerNextExpiry(expiredAt)
+	m.data[key] = expiredElement[V]{
+		expiredAt: expiredAt,
+		createdAt: createdAt,
+		updatedAt: now,
+		data:      data,
+		id:        m.lastID,
+		pos:       pos,
+		ttl:       duration,
+	}
+	if !found {
+		m.groupAdd(key)
+	}
+	m.notifyWaiters(key, data)
+	if existed {
+		m.notifyWatches(EventUpdated, key, data)
+	} else {
+		m.notifyWatches(EventAdded, key, data)
+	}
+	return old, existed
+}
+
+// entry returns Entry of the element.
+func (m *timeExpiredMap[K, V]) entry(key K, e expiredElement[V], now time.Time) Entry[K, V] {
+	return Entry[K, V]{
+		Key:       key,
+		Value:     e.data,
+		TTL:       remaining(e.expiredAt, now),
+		CreatedAt: e.createdAt,
+		UpdatedAt: e.updatedAt,
+	}
+}
+
+// GetEntry returns unexpired element by key with its remaining duration and timestamps. It returns the same errors as
+// Get.
+func (m *timeExpiredMap[K, V]) GetEntry(key K) (Entry[K, V], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, now, err := m.lookup(key)
+	if err != nil {
+		return Entry[K, V]{}, err
+	}
+	return m.entry(key, e, now), nil
+}
+
+// GetAllEntries returns unexpired elements with remaining duration in TTL.
+func (m *timeExpiredMap[K, V]) GetAllEntries() []Entry[K, V] {
+	var result []Entry[K, V]
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.clock.Now()
+	for key, e := range m.data {
+		if e.expiredAt.After(now) {
+			result = append(result, m.entry(key, e, now))
+		}
+	}
+	return result
+}
+
+// Cursor returns cursor over unexpired elements of the map, which returns them in batches.
+func (m *timeExpiredMap[K, V]) Cursor() *MapCursor[K, V] {
+	return NewMapCursor[K, V](m)
+}
+
+// Keys returns keys of unexpired elements.
+func (m *timeExpiredMap[K, V]) Keys() []K {
+	var result []K
+	m.Range(func(key K, value V) bool {
+		result = append(result, key)
+		return true
+	})
+	return result
+}
+
+// Values returns values of unexpired elements.
+func (m *timeExpiredMap[K, V]) Values() []V {
+	var result []V
+	m.Range(func(key K, value V) bool {
+		result = append(result, value)
+		return true
+	})
+	return result
+}
+
+// Range calls fn for every unexpired element until fn returns false, like Range of sync.Map. Unlike GetAllEntries it
+// doesn't copy the elements. The map is locked during iteration, so fn must not call methods of the map.
+func (m *timeExpiredMap[K, V]) Range(fn func(key K, value V) bool) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.data[key] = expiredElement[V]{expiredAt: time.Now().Add(duration), data: data}
+	now := m.clock.Now()
+	for key, e := range m.data {
+		if e.expiredAt.Before(now) {
+			continue
+		}
+		if !fn(key, e.data) {
+			return
+		}
+	}
 }
 
-// Get method returns element by key.
+// SortOrder is order of iteration.
+type SortOrder int
+
+const (
+	Ascending  SortOrder = iota // Ascending order, ex. soonest expiring elements first.
+	Descending                  // Descending order, ex. latest expiring elements first.
+)
+
+// IterateByExpiry calls fn for unexpired elements sorted by expiration until fn returns false. Ascending order starts
+// with elements which expire soonest, ex. for jobs refreshing elements at risk of expiration. Elements are copied
+// before iteration, so fn can call methods of the map.
+func (m *timeExpiredMap[K, V]) IterateByExpiry(order SortOrder, fn func(e Entry[K, V]) bool) {
+	entries := m.GetAllEntries()
+	sort.Slice(entries, func(i, j int) bool {
+		if order == Descending {
+			return entries[i].TTL > entries[j].TTL
+		}
+		return entries[i].TTL < entries[j].TTL
+	})
+	for _, e := range entries {
+		if !fn(e) {
+			return
+		}
+	}
+}
+
+// Get method returns element by key. If Config.EarlyExpirationBeta is set, it can return ExpiredError before the
+// element expires.
 func (m *timeExpiredMap[K, V]) Get(key K) (V, error) {
 	var result V
-	if !m.Contains(key) {
-		return result, ErrKeyNotFound
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, now, err := m.lookup(key)
+	if err != nil {
+		return result, err
+	}
+	if m.config.expiresEarly(e.expiredAt, now) {
+		return result, &ExpiredError{Key: key, ExpiredAt: now}
+	}
+	m.touch(key, e)
+	return e.data, nil
+}
+
+// GetManyConsistent returns unexpired elements of keys read under one lock, so related elements, ex. user and its
+// permissions, are observed at the same moment. Keys which are not in the map or expired are not in the result.
+func (m *timeExpiredMap[K, V]) GetManyConsistent(keys []K) map[K]V {
+	result := make(map[K]V, len(keys))
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		if e, _, err := m.lookup(key); err == nil {
+			result[key] = e.data
+		}
+	}
+	return result
+}
+
+// GetValidFor returns element by key only if it stays unexpired at least for minRemaining duration. It returns
+// ErrExpiringSoon if the element expires sooner, ex. to not start long operation with a token expiring in the middle of
+// it. Other errors are the same as of Get.
+func (m *timeExpiredMap[K, V]) GetValidFor(key K, minRemaining time.Duration) (V, error) {
+	var result V
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, now, err := m.lookup(key)
+	if err != nil {
+		return result, err
+	}
+	if remaining(e.expiredAt, now) < minRemaining {
+		return result, ErrExpiringSoon
+	}
+	return e.data, nil
+}
+
+// lookup returns unexpired element by key and current time. Element rejected by the validator is removed. It must
+// be called with locked mutex.
+func (m *timeExpiredMap[K, V]) lookup(key K) (expiredElement[V], time.Time, error) {
+	if m.closed {
+		return expiredElement[V]{}, time.Time{}, ErrClosed
+	}
+	e, found := m.data[key]
+	if !found {
+		return expiredElement[V]{}, time.Time{}, &KeyError{Key: key}
+	}
+	now := m.clock.Now()
+	if e.expiredAt.Before(now) {
+		return expiredElement[V]{}, now, &ExpiredError{Key: key, ExpiredAt: e.expiredAt}
+	}
+	if m.validator != nil && !m.validator(key, e.data) {
+		m.expire(key, e)
+		return expiredElement[V]{}, now, &ExpiredError{Key: key, ExpiredAt: now}
+	}
+	return e, now, nil
+}
+
+// touch extends the element by its duration if Config.SlidingExpiration is set. It returns the extended element. It
+// must be called with locked mutex.
+func (m *timeExpiredMap[K, V]) touch(key K, e expiredElement[V]) expiredElement[V] {
+	if !m.config.SlidingExpiration {
+		return e
+	}
+	e.expiredAt = m.clock.expiry(e.ttl)
+	e.warned = false
+	m.data[key] = e
+	return e
+}
+
+// SetTTL changes duration of unexpired element without rewriting its value. The duration is counted from now. If it's
+// 0, then default duration of the map is used. It returns KeyError if the key is not in the map and ErrInvalidTTL if
+// the duration is negative.
+func (m *timeExpiredMap[K, V]) SetTTL(key K, duration time.Duration) error {
+	if duration < 0 {
+		return ErrInvalidTTL
+	}
+	if duration == 0 {
+		duration = m.duration
+	}
+	duration, err := m.config.clampTTL(duration)
+	if err != nil {
+		return err
 	}
+	return m.setExpiration(key, duration)
+}
+
+// Persist removes expiration of unexpired element, so it never expires. If Config.MaxTTL is set, then it's applied the
+// same way as to NoExpiry duration. It returns KeyError if the key is not in the map.
+func (m *timeExpiredMap[K, V]) Persist(key K) error {
+	duration, err := m.config.clampTTL(NoExpiry)
+	if err != nil {
+		return err
+	}
+	return m.setExpiration(key, duration)
+}
+
+// setExpiration sets expiration of unexpired element to duration from now.
+func (m *timeExpiredMap[K, V]) setExpiration(key K, duration time.Duration) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	if m.data[key].expiredAt.Before(time.Now()) {
-		return result, ErrExpired
+	if m.closed {
+		return ErrClosed
 	}
-	return m.data[key].data, nil
+	e, found := m.data[key]
+	if !found || e.expiredAt.Before(m.clock.Now()) {
+		return &KeyError{Key: key}
+	}
+	m.uncount(e)
+	e.expiredAt = m.clock.expiry(duration)
+	e.ttl = duration
+	e.warned = false
+	m.lowerNextExpiry(e.expiredAt)
+	m.data[key] = e
+	return nil
 }
 
 // Del method removes element from map.
 func (m *timeExpiredMap[K, V]) Del(key K) error {
-	if !m.Contains(key) {
-		return ErrKeyNotFound
-	}
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	delete(m.data, key)
+	if m.closed {
+		return ErrClosed
+	}
+	e, found := m.data[key]
+	if !found || e.expiredAt.Before(m.clock.Now()) {
+		return &KeyError{Key: key}
+	}
+	m.remove(key)
 	return nil
 }
 
+// GetDel returns unexpired element by key and removes it from the map under one lock, so only one caller gets the
+// element, ex. for one-time tokens. Removed element is not sent to expired element channel. Errors are the same as of
+// Get.
+func (m *timeExpiredMap[K, V]) GetDel(key K) (V, error) {
+	var result V
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, _, err := m.lookup(key)
+	if err != nil {
+		return result, err
+	}
+	m.remove(key)
+	return e.data, nil
+}
+
+// GetOrCompute returns unexpired element by key. If the key is not in the map, it calls fn, adds its result with default
+// duration and returns it. Concurrent calls of the same key call fn only once and share its result. Error of fn is
+// returned and nothing is added. The map is not locked while fn runs, so fn can call methods of the map. If fn panics,
+// concurrent calls return ErrComputePanicked and the panic continues in the calling goroutine.
+func (m *timeExpiredMap[K, V]) GetOrCompute(key K, fn func() (V, error)) (V, error) {
+	return m.compute(key, func() (V, time.Duration, error) {
+		value, err := fn()
+		return value, m.duration, err
+	})
+}
+
+// compute is GetOrCompute where fn returns also duration of its result.
+func (m *timeExpiredMap[K, V]) compute(key K, fn func() (V, time.Duration, error)) (V, error) {
+	m.mu.Lock()
+	e, now, err := m.lookup(key)
+	if err == nil && !m.config.expiresEarly(e.expiredAt, now) {
+		m.mu.Unlock()
+		return e.data, nil
+	}
+	if errors.Is(err, ErrClosed) {
+		m.mu.Unlock()
+		return e.data, err
+	}
+	if c, found := m.computes[key]; found {
+		m.mu.Unlock()
+		<-c.done
+		return c.result.value, c.result.err
+	}
+	c := &memoCall[V]{done: make(chan struct{})}
+	if m.computes == nil {
+		m.computes = make(map[K]*memoCall[V])
+	}
+	m.computes[key] = c
+	m.mu.Unlock()
+
+	defer func() {
+		r := recover()
+		if r != nil {
+			c.result = memoResult[V]{err: fmt.Errorf("%w: %v: %v", ErrComputePanicked, key, r)}
+		}
+		m.mu.Lock()
+		delete(m.computes, key)
+		m.mu.Unlock()
+		close(c.done)
+		if r != nil {
+			panic(r)
+		}
+	}()
+	var duration time.Duration
+	c.result.value, duration, c.result.err = fn()
+	if c.result.err == nil {
+		m.add(key, c.result.value, duration)
+	}
+	return c.result.value, c.result.err
+}
+
+// AddWithCallback adds element to the map with default duration and callback, which is called in new goroutine when
+// the element expires. The callback is not called if the element is replaced or deleted before it expires.
+func (m *timeExpiredMap[K, V]) AddWithCallback(key K, data V, fn func(key K, value V)) {
+	duration, err := m.prepareAdd(key, data, m.duration)
+	if err != nil {
+		return
+	}
+	defer m.mu.Unlock()
+	m.set(key, data, duration)
+	if m.callbacks == nil {
+		m.callbacks = make(map[K]func(K, V))
+	}
+	m.callbacks[key] = fn
+}
+
 // Contains method returns true if key is in the map. Else return false.
 func (m *timeExpiredMap[K, V]) Contains(key K) bool {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	e, found := m.data[key]
-	if e.expiredAt.Before(time.Now()) {
+	if e.expiredAt.Before(m.clock.Now()) {
 		// if element expire, then return false
 		return false
 	}
+	if found {
+		m.touch(key, e)
+	}
 	return found
 }
 
-// Size method returns size of the map.
+// Size method returns number of unexpired elements in the map. The number of expired elements is maintained on changes
+// of the map and counted again only when some element expired since the last count. Until then Size doesn't iterate
+// the map, but when elements expire continuously, Size iterates the map like GetAll.
 func (m *timeExpiredMap[K, V]) Size() int {
-	var count = 0
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	for _, d := range m.data {
-		// Don't count if element already expired.
-		if d.expiredAt.After(time.Now()) {
-			count++
+	if now := m.clock.Now(); !now.Before(m.nextExpiry) {
+		m.recount(now)
+	}
+	return len(m.data) - m.expiredCount
+}
+
+// recount counts expired elements and finds the next expiration. Until the next expiration Size doesn't iterate the
+// map. It must be called with locked mutex.
+func (m *timeExpiredMap[K, V]) recount(now time.Time) {
+	m.expiredCount = 0
+	m.countedAt = now
+	m.nextExpiry = noExpiryTime
+	for _, e := range m.data {
+		if !e.expiredAt.After(now) {
+			m.expiredCount++
+		} else if e.expiredAt.Before(m.nextExpiry) {
+			m.nextExpiry = e.expiredAt
 		}
 	}
-	return count
+}
+
+// uncount removes element which is removed or changed from counted expired elements. It must be called with locked
+// mutex.
+func (m *timeExpiredMap[K, V]) uncount(e expiredElement[V]) {
+	if !e.expiredAt.After(m.countedAt) {
+		m.expiredCount--
+	}
+}
+
+// lowerNextExpiry makes Size count elements again at expiredAt if it's before the next expiration. It must be called
+// with locked mutex.
+func (m *timeExpiredMap[K, V]) lowerNextExpiry(expiredAt time.Time) {
+	if expiredAt.Before(m.nextExpiry) {
+		m.nextExpiry = expiredAt
+	}
+}
+
+// SizeRaw returns size of the map including expired elements which are not removed yet. It's for debugging of the
+// memory usage, ex. to distinguish logically removed elements from not yet reclaimed ones.
+func (m *timeExpiredMap[K, V]) SizeRaw() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.data)
+}
+
+// GetAllRaw returns all elements including expired ones which are not removed yet. TTL of expired elements is
+// negative.
+func (m *timeExpiredMap[K, V]) GetAllRaw() []Entry[K, V] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.clock.Now()
+	result := make([]Entry[K, V], 0, len(m.data))
+	for key, e := range m.data {
+		result = append(result, m.entry(key, e, now))
+	}
+	return result
 }
 
 // Clear function clear all elements from map.
 func (m *timeExpiredMap[K, V]) Clear() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	if m.closed {
+		return
+	}
 
 	m.data = make(map[K]expiredElement[V])
+	m.refreshers = nil
+	m.callbacks = nil
+	m.tombstones = nil
+	for key := range m.contexts {
+		m.releaseContext(key)
+	}
+	m.keys = nil
+	m.expiredCount = 0
+	m.removed = 0
+	m.groupKeys = nil
+	for group, stats := range m.groups {
+		stats.Size = 0
+		m.groups[group] = stats
+	}
 }
 
-// Discard method stops the goroutine for removing elements and discards data in internal map.
+// Discard method stops the goroutine for removing elements and discards data in internal map. After Discard the map
+// is closed, methods returning error return ErrClosed and other methods do nothing.
 func (m *timeExpiredMap[K, V]) Discard() {
-	m.quitChan <- struct{}{}
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	if m.closed {
+		return
+	}
+	if !m.stopped {
+		close(m.quitChan)
+	}
+	m.closed = true
+	m.clock.stop()
+	close(m.discardChan)
+	m.closeWatches()
+	var zero V
+	for key, w := range m.waiters {
+		w.release(zero, ErrClosed)
+		delete(m.waiters, key)
+	}
+	if m.config.Register {
+		unregister(m)
+	}
 	m.data = nil
+	m.keys = nil
+	m.contexts = nil
+	m.expiredCount = 0
+}
+
+// Stop method stops the goroutine for removing elements. Data in the map are retained and expired elements stay in
+// the map until Start is called.
+func (m *timeExpiredMap[K, V]) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed || m.stopped {
+		return
+	}
+	m.stopped = true
+	close(m.quitChan)
+}
+
+// Start method starts again the goroutine for removing elements stopped by Stop. It returns ErrClosed if the map was
+// discarded.
+func (m *timeExpiredMap[K, V]) Start() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return ErrClosed
+	}
+	if !m.stopped {
+		return nil
+	}
+	m.stopped = false
+	m.quitChan = make(chan struct{})
+	startCleaner(m.config, m.quitChan, m.removeExpired)
+	return nil
+}
+
+// Drain waits until all elements present in the map at the time of the call expire, are deleted or replaced. Expired
+// elements are removed and sent to expired element channel the same way as by the cleaning goroutine. It returns
+// context error if the context ends before.
+func (m *timeExpiredMap[K, V]) Drain(ctx context.Context) error {
+	m.mu.Lock()
+	pending := make(map[K]time.Time, len(m.data))
+	for key, e := range m.data {
+		pending[key] = e.expiredAt
+	}
+	m.mu.Unlock()
+
+	return waitUntil(ctx, func() (bool, error) {
+		if m.IsClosed() {
+			return false, ErrClosed
+		}
+		m.removeExpired()
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		for key, expiredAt := range pending {
+			if e, found := m.data[key]; !found || !e.expiredAt.Equal(expiredAt) {
+				delete(pending, key)
+			}
+		}
+		return len(pending) == 0, nil
+	})
+}
+
+// WaitForEmpty waits until the map has no unexpired element. It returns context error if the context ends before.
+func (m *timeExpiredMap[K, V]) WaitForEmpty(ctx context.Context) error {
+	return waitUntil(ctx, func() (bool, error) {
+		if m.IsClosed() {
+			return false, ErrClosed
+		}
+		m.removeExpired()
+		return m.Size() == 0, nil
+	})
+}
+
+// WaitFor returns element by key. If the key is not in the map, it waits until the key is added or context ends. All
+// goroutines waiting for the same key receive the value. Waiting goroutines are released with ExpiredError if the key
+// is not added within the map duration, so abandoned waiters don't stay in the map forever. It returns context error
+// if the context ends before and ErrClosed if the map is discarded while waiting.
+func (m *timeExpiredMap[K, V]) WaitFor(ctx context.Context, key K) (V, error) {
+	var result V
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return result, ErrClosed
+	}
+	if e, found := m.data[key]; found && e.expiredAt.After(m.clock.Now()) {
+		m.mu.Unlock()
+		return e.data, nil
+	}
+	if m.waiters == nil {
+		m.waiters = make(map[K]*keyWaiters[V])
+	}
+	w, found := m.waiters[key]
+	if !found {
+		w = &keyWaiters[V]{ready: make(chan struct{})}
+		m.waiters[key] = w
+	}
+	w.count++
+	w.expiredAt = m.clock.expiry(m.duration)
+	m.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return w.data, w.err
+	case <-ctx.Done():
+		m.mu.Lock()
+		w.count--
+		if w.count == 0 && m.waiters[key] == w {
+			delete(m.waiters, key)
+		}
+		m.mu.Unlock()
+		return result, ctx.Err()
+	}
+}
+
+// keyWaiters holds goroutines waiting for the key in WaitFor.
+type keyWaiters[V any] struct {
+	ready     chan struct{} // closed when the data or err is set
+	data      V
+	err       error
+	count     int       // number of waiting goroutines
+	expiredAt time.Time // waiting goroutines are released with error after this time
+}
+
+// release sets data and error and wakes up all waiting goroutines.
+func (w *keyWaiters[V]) release(data V, err error) {
+	w.data = data
+	w.err = err
+	close(w.ready)
+}
+
+// notifyWaiters sends value to all goroutines waiting for the key. It must be called with locked mutex.
+func (m *timeExpiredMap[K, V]) notifyWaiters(key K, data V) {
+	if w, found := m.waiters[key]; found {
+		w.release(data, nil)
+		delete(m.waiters, key)
+	}
+}
+
+// removeExpiredWaiters releases goroutines which wait for the key longer than the map duration. It must be called
+// with locked mutex.
+func (m *timeExpiredMap[K, V]) removeExpiredWaiters() {
+	var zero V
+	for key, w := range m.waiters {
+		if w.expiredAt.Before(m.clock.Now()) {
+			w.release(zero, &ExpiredError{Key: key, ExpiredAt: w.expiredAt})
+			delete(m.waiters, key)
+		}
+	}
+}
+
+// Name returns name of the map set in Config.
+func (m *timeExpiredMap[K, V]) Name() string {
+	return m.config.Name
+}
+
+// IsClosed returns true if the map was discarded.
+func (m *timeExpiredMap[K, V]) IsClosed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closed
 }
 
 func (m *timeExpiredMap[K, V]) ExpiredElChan() chan V {
 	return m.expiredChan
 }
 
-// run method runs the goroutine for removing expired elements.
-func (m *timeExpiredMap[K, V]) run() {
-	ticker := time.NewTicker(m.config.CleanJobInterval)
-	defer ticker.Stop()
+// ReplacedElChan returns channel with old values overwritten by Add or Swap. It's used only if
+// Config.ReplacedElChanSize is bigger than 0.
+func (m *timeExpiredMap[K, V]) ReplacedElChan() chan V {
+	return m.replacedChan
+}
 
-	for {
-		select {
-		case <-ticker.C:
-			m.removeExpired()
-		case <-m.quitChan:
-			return
+// EvictedElChan returns channel with elements evicted because of Config.GroupMaxLen. It's used only if
+// Config.EvictedElChanSize is bigger than 0.
+func (m *timeExpiredMap[K, V]) EvictedElChan() chan V {
+	return m.evictedChan
+}
+
+// shiftExpirations moves expirations of all elements by d. It must be called with locked mutex.
+func (m *timeExpiredMap[K, V]) shiftExpirations(d time.Duration) {
+	for key, e := range m.data {
+		e.expiredAt = shiftExpiry(e.expiredAt, d)
+		m.data[key] = e
+	}
+	m.recount(m.clock.Now())
+}
+
+// sendAboutToExpire sends elements which expire within Config.AboutToExpireLead to about to expire channel. It must be
+// called with locked mutex.
+func (m *timeExpiredMap[K, V]) sendAboutToExpire() {
+	if m.config.AboutToExpireLead <= 0 || cap(m.aboutChan) == 0 {
+		return
+	}
+	now := m.clock.Now()
+	deadline := now.Add(m.config.AboutToExpireLead)
+	for key, e := range m.data {
+		if e.warned || e.expiredAt.Before(now) || e.expiredAt.After(deadline) {
+			continue
 		}
+		e.warned = true
+		m.data[key] = e
+		sendDropOldest(m.aboutChan, m.entry(key, e, now))
+	}
+}
+
+// AboutToExpireChan returns channel with elements which expire within Config.AboutToExpireLead. It's used only if
+// Config.AboutToExpireChanSize is bigger than 0.
+func (m *timeExpiredMap[K, V]) AboutToExpireChan() chan Entry[K, V] {
+	return m.aboutChan
+}
+
+// vetoExpiration returns true if Config.BeforeEvict vetoed expiration of the element. Vetoed element is extended by
+// Config.EvictGrace. Every element can be vetoed only once. It must be called with locked mutex.
+func (m *timeExpiredMap[K, V]) vetoExpiration(key K, e expiredElement[V], now time.Time) bool {
+	if m.config.BeforeEvict == nil || e.vetoed || !m.config.BeforeEvict(key, e.data) {
+		return false
 	}
+	m.uncount(e)
+	e.vetoed = true
+	e.expiredAt = now.Add(m.config.EvictGrace)
+	m.data[key] = e
+	return true
+}
+
+// expire removes expired element, sends it to expired element channel and notifies watches. It must be called with
+// locked mutex.
+func (m *timeExpiredMap[K, V]) expire(key K, e expiredElement[V]) {
+	if fn, found := m.callbacks[key]; found {
+		go fn(key, e.data)
+	}
+	m.remove(key)
+	sendDropOldest(m.expiredChan, e.data)
+	m.notifyWatches(EventExpired, key, e.data)
+}
+
+// remove deletes element from the map and its quota group. It must be called with locked mutex.
+func (m *timeExpiredMap[K, V]) remove(key K) {
+	e, found := m.data[key]
+	if !found {
+		return
+	}
+	delete(m.data, key)
+	delete(m.refreshers, key)
+	delete(m.callbacks, key)
+	m.releaseContext(key)
+	m.removeKey(e.pos)
+	m.uncount(e)
+	m.removed++
+	if m.config.GroupFn != nil {
+		m.groupRemove(key)
+	}
+}
+
+// releaseContext ends goroutine of AddWithContext of the key. It must be called with locked mutex.
+func (m *timeExpiredMap[K, V]) releaseContext(key K) {
+	if removed, found := m.contexts[key]; found {
+		close(removed)
+		delete(m.contexts, key)
+	}
+}
+
+// checkSize checks size of the map by Config.SizeWatch.
+func (m *timeExpiredMap[K, V]) checkSize() {
+	m.mu.Lock()
+	alerts := m.sizeWatch.check(m.config, len(m.data))
+	m.mu.Unlock()
+	m.config.SizeWatch.notify(alerts)
 }
 
 // removeExpired method removes expired elements.
 func (m *timeExpiredMap[K, V]) removeExpired() {
+	m.suspend.handle(m.config, &m.mu, m.shiftExpirations)
+	defer m.checkSize()
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	m.sendAboutToExpire()
+	now := m.clock.Now()
+	m.startRefreshes(now)
 	for key, val := range m.data {
-		if val.expiredAt.Before(time.Now()) {
-			// If expired element channel is defined and size is bigger than 0, than send expired element to this channel.
-			if cap(m.expiredChan) > 0 {
-				if len(m.expiredChan) >= m.config.ExpiredElChanSize {
-					// If expired element channel is full then remove first element.
-					<-m.expiredChan
-				}
-				// Send expired element to expired element channel.
-				m.expiredChan <- m.data[key].data
-			}
-			// Delete element from map.
-			delete(m.data, key)
+		if val.expiredAt.Before(now) && !m.vetoExpiration(key, val, now) {
+			m.expire(key, val)
 		}
 	}
+	m.rebuild()
+	m.recount(now)
+	m.removeExpiredTombstones(now)
+	m.removeExpiredWaiters()
 }
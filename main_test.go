@@ -1,10 +1,14 @@
 package gocollections
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/rand"
+	"runtime"
+	"sort"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 )
@@ -375,3 +379,1058 @@ func TestTimeExpiredMap_ExpiredElChan(t *testing.T) {
 		return
 	}
 }
+
+func TestTimeExpiredMap_ScreenReject(t *testing.T) {
+	t.Parallel()
+
+	var screened []any
+	tmap := NewTimeExpiredMap[string, string](10*time.Second, Config{
+		ScreenMode: ScreenReject,
+		OnScreened: func(key, value any) {
+			screened = append(screened, key)
+		},
+	})
+	defer tmap.Discard()
+
+	tmap.Add("", "test 1")
+	tmap.Add("1", "test 1")
+
+	if tmap.Size() != 1 {
+		t.Errorf("want: %d, got: %d", 1, tmap.Size())
+	}
+	if tmap.Contains("") {
+		t.Error("zero-value key should be rejected")
+	}
+	if len(screened) != 1 {
+		t.Errorf("OnScreened should be called once, but was called %d times", len(screened))
+	}
+}
+
+func TestTimeExpiredList_ScreenWarn(t *testing.T) {
+	t.Parallel()
+
+	var screened int
+	tlist := NewTimeExpiredList[string](10*time.Second, Config{
+		ScreenMode: ScreenWarn,
+		ScreenFunc: func(key, value any) bool {
+			return value == "invalid"
+		},
+		OnScreened: func(key, value any) {
+			screened++
+		},
+	})
+	defer tlist.Discard()
+
+	tlist.Add("invalid")
+	tlist.Add("")
+
+	if tlist.Size() != 2 {
+		t.Errorf("want: %d, got: %d", 2, tlist.Size())
+	}
+	if screened != 1 {
+		t.Errorf("OnScreened should be called once, but was called %d times", screened)
+	}
+}
+
+func TestTimeExpiredMap_Discard(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](10 * time.Second)
+	tmap.Add("1", "test 1")
+	tmap.Discard()
+	tmap.Discard()
+
+	if !tmap.IsClosed() {
+		t.Error("map should be closed after Discard")
+	}
+	tmap.Add("2", "test 2")
+	if _, err := tmap.Get("2"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expect ErrClosed but got %v", err)
+	}
+	if err := tmap.Del("1"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expect ErrClosed but got %v", err)
+	}
+	if tmap.Size() != 0 {
+		t.Errorf("want: %d, got: %d", 0, tmap.Size())
+	}
+}
+
+func TestTimeExpiredList_Discard(t *testing.T) {
+	t.Parallel()
+
+	tlist := NewTimeExpiredList[string](10 * time.Second)
+	tlist.Add("value1")
+	tlist.Discard()
+	tlist.Discard()
+
+	if !tlist.IsClosed() {
+		t.Error("list should be closed after Discard")
+	}
+	tlist.Add("value2")
+	if tlist.Size() != 0 {
+		t.Errorf("want: %d, got: %d", 0, tlist.Size())
+	}
+	if _, err := tlist.Get(0); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expect ErrClosed but got %v", err)
+	}
+}
+
+func TestTimeExpiredMap_StopStart(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](100*time.Millisecond, Config{
+		CleanJobInterval:  50 * time.Millisecond,
+		ExpiredElChanSize: 10,
+	})
+	defer tmap.Discard()
+
+	tmap.Stop()
+	tmap.Add("1", "test 1")
+	time.Sleep(300 * time.Millisecond)
+
+	// Element expired, but it's not removed while cleaning goroutine is stopped.
+	if len(tmap.ExpiredElChan()) != 0 {
+		t.Errorf("Expect no removed element while stopped, but got %d", len(tmap.ExpiredElChan()))
+	}
+
+	if err := tmap.Start(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-tmap.ExpiredElChan():
+	case <-time.After(1 * time.Second):
+		t.Error("No expired element in timeout after Start")
+	}
+
+	tmap.Discard()
+	if err := tmap.Start(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expect ErrClosed but got %v", err)
+	}
+}
+
+func TestTimeExpiredMap_Drain(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](100 * time.Millisecond)
+	defer tmap.Discard()
+
+	tmap.Add("1", "test 1")
+	tmap.Add("2", "test 2")
+	_ = tmap.Del("2")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+	if err := tmap.Drain(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(tmap.ExpiredElChan()) != 1 {
+		t.Errorf("Expect 1 element in expired element channel, but got %d", len(tmap.ExpiredElChan()))
+	}
+
+	tmap.Add("3", "test 3")
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := tmap.WaitForEmpty(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expect context.DeadlineExceeded but got %v", err)
+	}
+}
+
+func TestTimeExpiredList_WaitForEmpty(t *testing.T) {
+	t.Parallel()
+
+	tlist := NewTimeExpiredList[string](100 * time.Millisecond)
+	defer tlist.Discard()
+
+	tlist.Add("value1")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+	if err := tlist.WaitForEmpty(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if tlist.Size() != 0 {
+		t.Errorf("want: %d, got: %d", 0, tlist.Size())
+	}
+}
+
+func TestTimeExpiredMap_Name(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](10*time.Second, Config{
+		Name:   "session-cache",
+		Labels: map[string]string{"team": "core"},
+	})
+	defer tmap.Discard()
+
+	if tmap.Name() != "session-cache" {
+		t.Errorf("want: %s, got: %s", "session-cache", tmap.Name())
+	}
+}
+
+func TestTimeExpiredMap_WaitFor(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](10 * time.Second)
+	defer tmap.Discard()
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		tmap.Add("1", "test 1")
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+	val, err := tmap.WaitFor(ctx, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val != "test 1" {
+		t.Errorf("want: %s, got: %s", "test 1", val)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err = tmap.WaitFor(ctx, "2"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expect context.DeadlineExceeded but got %v", err)
+	}
+}
+
+func TestTimeExpiredMap_WaitForBroadcast(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](100*time.Millisecond, Config{
+		CleanJobInterval: 50 * time.Millisecond,
+	})
+	defer tmap.Discard()
+
+	results := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			val, err := tmap.WaitFor(context.Background(), "1")
+			if err == nil && val != "test 1" {
+				err = fmt.Errorf("want: %s, got: %s", "test 1", val)
+			}
+			results <- err
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	tmap.Add("1", "test 1")
+	for i := 0; i < 3; i++ {
+		if err := <-results; err != nil {
+			t.Error(err)
+		}
+	}
+
+	// Abandoned waiter is released after map duration.
+	_, err := tmap.WaitFor(context.Background(), "2")
+	if !errors.Is(err, ErrExpired) {
+		t.Errorf("Expect ErrExpired but got %v", err)
+	}
+}
+
+func TestTimeExpiredMap_Swap(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](10*time.Second, Config{
+		ReplacedElChanSize: 1,
+	})
+	defer tmap.Discard()
+
+	old, existed := tmap.Swap("1", "test 1")
+	if existed || old != "" {
+		t.Errorf("Expect no previous value, but got: %s", old)
+	}
+
+	old, existed = tmap.Swap("1", "test 2")
+	if !existed || old != "test 1" {
+		t.Errorf("want: %s, got: %s", "test 1", old)
+	}
+
+	tmap.Add("1", "test 3")
+	select {
+	case el := <-tmap.ReplacedElChan():
+		// Channel has size 1, so the oldest replaced value was dropped.
+		if el != "test 2" {
+			t.Errorf("want: %s, got: %s", "test 2", el)
+		}
+	default:
+		t.Error("No replaced element in channel")
+	}
+}
+
+func TestTimeExpiredMap_AddAll(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](10*time.Second, Config{
+		ScreenMode: ScreenReject,
+	})
+	defer tmap.Discard()
+
+	errs := tmap.AddAll([]Entry[string, string]{
+		{Key: "1", Value: "test 1"},
+		{Key: "2", Value: "test 2", TTL: -1 * time.Second},
+		{Key: "", Value: "test 3"},
+		{Key: "4", Value: "test 4", TTL: 100 * time.Millisecond},
+	})
+	if len(errs) != 4 {
+		t.Fatalf("Expect 4 errors, but got %d", len(errs))
+	}
+	if errs[0] != nil || errs[3] != nil {
+		t.Errorf("Expect valid entries without error, but got: %v, %v", errs[0], errs[3])
+	}
+	if !errors.Is(errs[1], ErrInvalidTTL) {
+		t.Errorf("Expect ErrInvalidTTL but got %v", errs[1])
+	}
+	if !errors.Is(errs[2], ErrRejected) {
+		t.Errorf("Expect ErrRejected but got %v", errs[2])
+	}
+	if tmap.Size() != 2 {
+		t.Errorf("want: %d, got: %d", 2, tmap.Size())
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	if tmap.Contains("4") {
+		t.Error("entry with custom TTL should expire")
+	}
+	if errs = tmap.AddAll([]Entry[string, string]{{Key: "5", Value: "test 5"}}); errs != nil {
+		t.Errorf("Expect no errors, but got %v", errs)
+	}
+}
+
+func TestTimeExpiredList_MaxLen(t *testing.T) {
+	t.Parallel()
+
+	tlist := NewTimeExpiredList[string](10*time.Second, Config{
+		MaxLen:            3,
+		EvictedElChanSize: 10,
+	})
+	defer tlist.Discard()
+
+	tlist.Add("value1")
+	tlist.Add("value2")
+	tlist.Add("value3")
+	tlist.Add("value4")
+	tlist.Add("value5")
+
+	values := tlist.GetAll()
+	if len(values) != 3 || values[0] != "value3" {
+		t.Errorf("Expect values [value3 value4 value5], but got %v", values)
+	}
+	if len(tlist.EvictedElChan()) != 2 {
+		t.Fatalf("Expect 2 evicted elements, but got %d", len(tlist.EvictedElChan()))
+	}
+	if el := <-tlist.EvictedElChan(); el != "value1" {
+		t.Errorf("want: %s, got: %s", "value1", el)
+	}
+}
+
+func TestTimeExpiredList_Handle(t *testing.T) {
+	t.Parallel()
+
+	tlist := NewTimeExpiredList[string](10 * time.Second)
+	defer tlist.Discard()
+
+	h1 := tlist.AddWithHandle("value1")
+	h2 := tlist.AddWithHandle("value2")
+	h3 := tlist.AddWithHandle("value3")
+
+	if err := tlist.DelByHandle(h1); err != nil {
+		t.Fatal(err)
+	}
+	_ = tlist.Del(0)
+
+	got, err := tlist.GetByHandle(h3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "value3" {
+		t.Errorf("want: %s, got: %s", "value3", got)
+	}
+	if _, err = tlist.GetByHandle(h2); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Expect ErrKeyNotFound but got %v", err)
+	}
+}
+
+func TestTimeExpiredList_GetAllEntries(t *testing.T) {
+	t.Parallel()
+
+	tlist := NewTimeExpiredList[string](10 * time.Second)
+	defer tlist.Discard()
+
+	h := tlist.AddWithHandle("value1")
+
+	entries := tlist.GetAllEntries()
+	if len(entries) != 1 {
+		t.Fatalf("Expect 1 entry, but got %d", len(entries))
+	}
+	if entries[0].Value != "value1" || entries[0].Handle != h {
+		t.Errorf("Expect entry value1 with handle %d, but got %v", h, entries[0])
+	}
+	if ttl := entries[0].TTL(); ttl <= 9*time.Second || ttl > 10*time.Second {
+		t.Errorf("Expect TTL about 10s, but got %s", ttl)
+	}
+}
+
+func TestTimeExpiredList_Reversed(t *testing.T) {
+	t.Parallel()
+
+	tlist := NewTimeExpiredList[string](10 * time.Second)
+	defer tlist.Discard()
+
+	if _, err := tlist.Last(); !errors.Is(err, ErrIndexOutOfBound) {
+		t.Errorf("Expect ErrIndexOutOfBound but got %v", err)
+	}
+
+	tlist.Add("value1")
+	tlist.Add("value2")
+	tlist.Add("value3")
+
+	last, err := tlist.Last()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last != "value3" {
+		t.Errorf("want: %s, got: %s", "value3", last)
+	}
+
+	values := tlist.GetAllReversed()
+	if len(values) != 3 || values[0] != "value3" || values[2] != "value1" {
+		t.Errorf("Expect values [value3 value2 value1], but got %v", values)
+	}
+
+	var first []string
+	tlist.Iterate(Backward, func(value string) bool {
+		first = append(first, value)
+		return len(first) < 2
+	})
+	if len(first) != 2 || first[1] != "value2" {
+		t.Errorf("Expect values [value3 value2], but got %v", first)
+	}
+
+	expired := NewTimeExpiredList[string](-1 * time.Second)
+	defer expired.Discard()
+	expired.Add("value1")
+	var expiredErr *ExpiredError
+	if _, err := expired.Last(); !errors.As(err, &expiredErr) || expiredErr.Key != 0 {
+		t.Errorf("Expect ExpiredError of index 0 but got %v", err)
+	}
+}
+
+func TestTimeExpiredList_Dedup(t *testing.T) {
+	t.Parallel()
+
+	tlist := NewDedupTimeExpiredList(10*time.Second, func(value string) string {
+		return value
+	})
+	defer tlist.Discard()
+
+	tlist.Add("value1")
+	tlist.Add("value2")
+	tlist.Add("value1")
+
+	values := tlist.GetAll()
+	if len(values) != 2 || values[0] != "value2" || values[1] != "value1" {
+		t.Errorf("Expect values [value2 value1], but got %v", values)
+	}
+}
+
+func TestTimeExpiredMap_Validator(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewValidatedTimeExpiredMap(10*time.Second, func(key, value string) bool {
+		return value != "broken"
+	}, Config{ExpiredElChanSize: 10})
+	defer tmap.Discard()
+
+	tmap.Add("key1", "value1")
+	tmap.Add("key2", "broken")
+
+	if val, err := tmap.Get("key1"); err != nil || val != "value1" {
+		t.Errorf("want: %s, got: %s, %v", "value1", val, err)
+	}
+	if _, err := tmap.Get("key2"); !errors.Is(err, ErrExpired) {
+		t.Errorf("Expect ErrExpired but got %v", err)
+	}
+	if tmap.Contains("key2") {
+		t.Error("Expect invalid element removed from the map")
+	}
+	if val := <-tmap.ExpiredElChan(); val != "broken" {
+		t.Errorf("Expect invalid element in expired channel, but got %s", val)
+	}
+}
+
+func TestTimeExpiredMap_Range(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, int](10 * time.Second)
+	defer tmap.Discard()
+
+	tmap.Add("key1", 1)
+	tmap.Add("key2", 2)
+	tmap.AddWithDuration("key3", 3, -time.Second)
+
+	sum := 0
+	tmap.Range(func(key string, value int) bool {
+		sum += value
+		return true
+	})
+	if sum != 3 {
+		t.Errorf("Expect sum of unexpired values 3, but got %d", sum)
+	}
+
+	calls := 0
+	tmap.Range(func(key string, value int) bool {
+		calls++
+		return false
+	})
+	if calls != 1 {
+		t.Errorf("Expect streaming stopped after first value, but fn was called %d times", calls)
+	}
+}
+
+func TestTimeExpiredMap_SizeRaw(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](10 * time.Second)
+	defer tmap.Discard()
+
+	tmap.Add("key1", "value1")
+	tmap.AddWithDuration("key2", "value2", -time.Second)
+
+	if size := tmap.Size(); size != 1 {
+		t.Errorf("Expect size 1, but got %d", size)
+	}
+	if size := tmap.SizeRaw(); size != 2 {
+		t.Errorf("Expect raw size 2, but got %d", size)
+	}
+	for _, e := range tmap.GetAllRaw() {
+		if e.Key == "key2" && e.TTL >= 0 {
+			t.Errorf("Expect negative TTL of expired element, but got %v", e.TTL)
+		}
+	}
+}
+
+func TestTimeExpiredMap_AddWithContext(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](10*time.Second, Config{ExpiredElChanSize: 10})
+	defer tmap.Discard()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tmap.AddWithContext(ctx, "key1", "value1")
+	if !tmap.Contains("key1") {
+		t.Fatal("Expect key1 in the map")
+	}
+
+	cancel()
+	select {
+	case val := <-tmap.ExpiredElChan():
+		if val != "value1" {
+			t.Errorf("Expect expired value1, but got %s", val)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expect element expired after context cancel")
+	}
+	if tmap.Contains("key1") {
+		t.Error("Expect key1 removed after context cancel")
+	}
+}
+
+func TestTimeExpiredMap_AddWithContextExtended(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](20*time.Millisecond, Config{ExpiredElChanSize: 10})
+	defer tmap.Discard()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tmap.AddWithContext(ctx, "key1", "value1")
+	if err := tmap.SetTTL("key1", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	cancel()
+	select {
+	case <-tmap.ExpiredElChan():
+	case <-time.After(time.Second):
+		t.Fatal("Expect extended element expired after context cancel")
+	}
+}
+
+// Test is not parallel, because it counts goroutines.
+func TestTimeExpiredMap_AddWithContextRemoved(t *testing.T) {
+	tmap := NewTimeExpiredMap[int, int](time.Minute)
+	defer tmap.Discard()
+
+	before := runtime.NumGoroutine()
+	for i := 0; i < 100; i++ {
+		tmap.AddWithContext(context.Background(), i, i)
+	}
+	for i := 0; i < 50; i++ {
+		_ = tmap.Del(i)
+	}
+	for i := 50; i < 100; i++ {
+		tmap.Add(i, i)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Errorf("Expect goroutines of removed and replaced elements ended, before: %d, after: %d", before, n)
+	}
+}
+
+func TestTimeExpiredMap_SetTTL(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](50 * time.Millisecond)
+	defer tmap.Discard()
+
+	tmap.Add("key1", "value1")
+	tmap.Add("key2", "value2")
+	if err := tmap.SetTTL("key1", 10*time.Second); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if err := tmap.Persist("key2"); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if err := tmap.SetTTL("key3", time.Second); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Expect ErrKeyNotFound but got %v", err)
+	}
+	if err := tmap.SetTTL("key1", -time.Second); !errors.Is(err, ErrInvalidTTL) {
+		t.Errorf("Expect ErrInvalidTTL but got %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if val, err := tmap.Get("key1"); err != nil || val != "value1" {
+		t.Errorf("want: %s, got: %s, %v", "value1", val, err)
+	}
+	if val, err := tmap.Get("key2"); err != nil || val != "value2" {
+		t.Errorf("want: %s, got: %s, %v", "value2", val, err)
+	}
+}
+
+func TestTimeExpiredMap_NoExpiry(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](NoExpiry, Config{CleanJobInterval: 10 * time.Millisecond})
+	defer tmap.Discard()
+
+	tmap.Add("key1", "value1")
+	tmap.AddWithDuration("key2", "value2", 20*time.Millisecond)
+	tmap.AddAll([]Entry[string, string]{{Key: "key3", Value: "value3", TTL: NoExpiry}})
+
+	time.Sleep(50 * time.Millisecond)
+	if tmap.SizeRaw() != 2 || tmap.Contains("key2") {
+		t.Errorf("Expect only permanent elements in the map, but got %v", tmap.GetAllEntries())
+	}
+	for _, e := range tmap.GetAllEntries() {
+		if e.TTL != NoExpiry {
+			t.Errorf("Expect TTL NoExpiry, but got %v", e.TTL)
+		}
+	}
+}
+
+func TestTimeExpiredMap_ClampTTL(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](time.Minute, Config{MinTTL: time.Second, MaxTTL: time.Hour})
+	defer tmap.Discard()
+
+	tmap.AddWithDuration("key1", "value1", 0)
+	tmap.AddWithDuration("key2", "value2", NoExpiry)
+	for _, e := range tmap.GetAllEntries() {
+		if e.TTL < 900*time.Millisecond || e.TTL > time.Hour {
+			t.Errorf("Expect TTL of %s clamped, but got %v", e.Key, e.TTL)
+		}
+	}
+
+	rmap := NewTimeExpiredMap[string, string](time.Minute, Config{MaxTTL: time.Hour, RejectTTLOutOfRange: true})
+	defer rmap.Discard()
+
+	rmap.AddWithDuration("key1", "value1", 24*time.Hour)
+	if rmap.Contains("key1") {
+		t.Error("Expect element with too long duration rejected")
+	}
+	errs := rmap.AddAll([]Entry[string, string]{{Key: "key2", Value: "value2", TTL: 24 * time.Hour}})
+	if len(errs) != 1 || !errors.Is(errs[0], ErrInvalidTTL) {
+		t.Errorf("Expect ErrInvalidTTL but got %v", errs)
+	}
+}
+
+func TestTimeExpiredMap_GetEntry(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](10 * time.Second)
+	defer tmap.Discard()
+
+	tmap.Add("key1", "value1")
+	time.Sleep(10 * time.Millisecond)
+	tmap.Add("key1", "value2")
+
+	e, err := tmap.GetEntry("key1")
+	if err != nil || e.Value != "value2" {
+		t.Fatalf("want: %s, got: %s, %v", "value2", e.Value, err)
+	}
+	if !e.UpdatedAt.After(e.CreatedAt) {
+		t.Errorf("Expect UpdatedAt %v after CreatedAt %v", e.UpdatedAt, e.CreatedAt)
+	}
+	if _, err := tmap.GetEntry("key2"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Expect ErrKeyNotFound but got %v", err)
+	}
+}
+
+func TestTimeExpiredMap_AboutToExpire(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](10*time.Second, Config{
+		CleanJobInterval:      10 * time.Millisecond,
+		AboutToExpireLead:     100 * time.Millisecond,
+		AboutToExpireChanSize: 10,
+	})
+	defer tmap.Discard()
+
+	tmap.AddWithDuration("key1", "value1", 150*time.Millisecond)
+	tmap.Add("key2", "value2")
+
+	select {
+	case e := <-tmap.AboutToExpireChan():
+		if e.Key != "key1" || !tmap.Contains("key1") {
+			t.Errorf("Expect unexpired key1 about to expire, but got %s", e.Key)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expect key1 about to expire")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if n := len(tmap.AboutToExpireChan()); n != 0 {
+		t.Errorf("Expect element sent once, but got %d more", n)
+	}
+}
+
+func TestTimeExpiredMap_GetValidFor(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](10 * time.Second)
+	defer tmap.Discard()
+
+	tmap.Add("key1", "value1")
+	tmap.AddWithDuration("key2", "value2", time.Second)
+
+	if val, err := tmap.GetValidFor("key1", 5*time.Second); err != nil || val != "value1" {
+		t.Errorf("want: %s, got: %s, %v", "value1", val, err)
+	}
+	if _, err := tmap.GetValidFor("key2", 5*time.Second); !errors.Is(err, ErrExpiringSoon) {
+		t.Errorf("Expect ErrExpiringSoon but got %v", err)
+	}
+	if _, err := tmap.GetValidFor("key3", time.Second); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Expect ErrKeyNotFound but got %v", err)
+	}
+}
+
+func TestTimeExpiredMap_SizeCounter(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](time.Minute)
+	defer tmap.Discard()
+	tmap.AddWithDuration("key1", "value1", 20*time.Millisecond)
+	tmap.Add("key2", "value2")
+	tmap.Add("key3", "value3")
+	_ = tmap.Del("key3")
+	if size := tmap.Size(); size != 2 {
+		t.Fatalf("want: %d, got: %d", 2, size)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if size := tmap.Size(); size != 1 {
+		t.Fatalf("want: %d, got: %d", 1, size)
+	}
+
+	// Re-adding counted expired element makes it unexpired.
+	tmap.Add("key1", "value1")
+	if size := tmap.Size(); size != 2 {
+		t.Errorf("want: %d, got: %d", 2, size)
+	}
+	if size := tmap.SizeRaw(); size != 2 {
+		t.Errorf("raw want: %d, got: %d", 2, size)
+	}
+}
+
+func TestTimeExpiredMap_GetDel(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](time.Minute)
+	defer tmap.Discard()
+	tmap.Add("token", "value")
+
+	results := make(chan error, 10)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := tmap.GetDel("token")
+			results <- err
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	taken := 0
+	for err := range results {
+		if err == nil {
+			taken++
+		} else if !errors.Is(err, ErrKeyNotFound) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if taken != 1 {
+		t.Errorf("token should be taken once, taken: %d", taken)
+	}
+	if tmap.Contains("token") {
+		t.Error("token should be removed")
+	}
+}
+
+func TestTimeExpiredMap_IterateByExpiry(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](time.Minute)
+	defer tmap.Discard()
+	tmap.AddWithDuration("hour", "value", time.Hour)
+	tmap.AddWithDuration("second", "value", time.Second)
+	tmap.Add("minute", "value")
+	tmap.AddWithDuration("never", "value", NoExpiry)
+
+	keys := func(order SortOrder, limit int) []string {
+		var result []string
+		tmap.IterateByExpiry(order, func(e Entry[string, string]) bool {
+			result = append(result, e.Key)
+			return len(result) < limit
+		})
+		return result
+	}
+
+	if got := fmt.Sprint(keys(Ascending, 10)); got != "[second minute hour never]" {
+		t.Errorf("ascending got: %s", got)
+	}
+	if got := fmt.Sprint(keys(Descending, 2)); got != "[never hour]" {
+		t.Errorf("descending got: %s", got)
+	}
+}
+
+func TestTimeExpiredMap_BeforeEvict(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	vetoes := 0
+	tmap := NewTimeExpiredMap[string, string](10*time.Millisecond, Config{
+		CleanJobInterval: 10 * time.Millisecond,
+		EvictGrace:       50 * time.Millisecond,
+		BeforeEvict: func(key, value any) bool {
+			mu.Lock()
+			defer mu.Unlock()
+			vetoes++
+			return key == "inUse"
+		},
+	})
+	defer tmap.Discard()
+	tmap.Add("inUse", "value")
+	tmap.Add("idle", "value")
+
+	time.Sleep(40 * time.Millisecond)
+	if !tmap.Contains("inUse") {
+		t.Error("vetoed element should be extended")
+	}
+	if tmap.Contains("idle") {
+		t.Error("not vetoed element should expire")
+	}
+
+	// Expiration is vetoed only once.
+	time.Sleep(80 * time.Millisecond)
+	if tmap.Contains("inUse") {
+		t.Error("element should expire after grace")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if vetoes != 2 {
+		t.Errorf("want: %d calls, got: %d", 2, vetoes)
+	}
+}
+
+func TestTimeExpiredMap_GetOrCompute(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, int](time.Minute)
+	defer tmap.Discard()
+
+	var mu sync.Mutex
+	calls := 0
+	compute := func() (int, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		return 42, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := tmap.GetOrCompute("key", compute); err != nil || v != 42 {
+				t.Errorf("want: %d, got: %d, %v", 42, v, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls != 1 {
+		t.Errorf("fn should be called once, calls: %d", calls)
+	}
+
+	failed := errors.New("failed")
+	if _, err := tmap.GetOrCompute("other", func() (int, error) { return 0, failed }); !errors.Is(err, failed) {
+		t.Errorf("want: %v, got: %v", failed, err)
+	}
+	if tmap.Contains("other") {
+		t.Error("failed result should not be added")
+	}
+
+	// Waiting call gets error of the panicked computation and next call computes again.
+	started := make(chan struct{})
+	waiting := make(chan error)
+	go func() {
+		defer func() {
+			_ = recover()
+		}()
+		_, _ = tmap.GetOrCompute("panic", func() (int, error) {
+			close(started)
+			time.Sleep(20 * time.Millisecond)
+			panic("boom")
+		})
+	}()
+	<-started
+	go func() {
+		_, err := tmap.GetOrCompute("panic", compute)
+		waiting <- err
+	}()
+	if err := <-waiting; !errors.Is(err, ErrComputePanicked) {
+		t.Errorf("want: %v, got: %v", ErrComputePanicked, err)
+	}
+	if v, err := tmap.GetOrCompute("panic", compute); err != nil || v != 42 {
+		t.Errorf("want: %d, got: %d, %v", 42, v, err)
+	}
+}
+
+func TestTimeExpiredMap_GetManyConsistent(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, int](time.Minute)
+	defer tmap.Discard()
+
+	// Writer keeps both keys equal, so consistent read never sees them different.
+	done := make(chan struct{})
+	go func() {
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			tmap.AddAll([]Entry[string, int]{{Key: "user", Value: i}, {Key: "permissions", Value: i}})
+		}
+	}()
+	defer close(done)
+
+	for i := 0; i < 1000; i++ {
+		values := tmap.GetManyConsistent([]string{"user", "permissions", "missing"})
+		if _, found := values["missing"]; found {
+			t.Fatal("missing key should not be in result")
+		}
+		if values["user"] != values["permissions"] {
+			t.Fatalf("inconsistent read: %v", values)
+		}
+	}
+}
+
+func TestTimeExpiredMap_SlidingExpiration(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](50*time.Millisecond, Config{SlidingExpiration: true})
+	defer tmap.Discard()
+	tmap.Add("read", "value")
+	tmap.Add("contains", "value")
+	tmap.Add("idle", "value")
+	tmap.Add("entry", "value")
+
+	for i := 0; i < 4; i++ {
+		time.Sleep(25 * time.Millisecond)
+		if _, err := tmap.Get("read"); err != nil {
+			t.Fatalf("element read within duration should not expire: %v", err)
+		}
+		if !tmap.Contains("contains") {
+			t.Fatal("element checked within duration should not expire")
+		}
+		_, _ = tmap.GetEntry("entry")
+	}
+	if _, err := tmap.Get("idle"); !errors.Is(err, ErrExpired) {
+		t.Errorf("not accessed element should expire, got: %v", err)
+	}
+	if _, err := tmap.Get("entry"); !errors.Is(err, ErrExpired) {
+		t.Errorf("element read only by GetEntry should expire, got: %v", err)
+	}
+}
+
+func TestTimeExpiredMap_AddWithCallback(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](20*time.Millisecond, Config{CleanJobInterval: 10 * time.Millisecond})
+	defer tmap.Discard()
+	expired := make(chan string, 10)
+	callback := func(key string, value string) {
+		expired <- key
+	}
+	tmap.AddWithCallback("expiring", "value", callback)
+	tmap.AddWithCallback("replaced", "value", callback)
+	tmap.Add("replaced", "value2")
+	tmap.AddWithCallback("deleted", "value", callback)
+	_ = tmap.Del("deleted")
+
+	select {
+	case key := <-expired:
+		if key != "expiring" {
+			t.Errorf("want: %s, got: %s", "expiring", key)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("callback not called")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if len(expired) != 0 {
+		t.Errorf("callback of replaced or deleted element should not be called, got: %s", <-expired)
+	}
+}
+
+func TestTimeExpiredMap_KeysValues(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, int](time.Minute)
+	defer tmap.Discard()
+	tmap.Add("a", 1)
+	tmap.Add("b", 2)
+	tmap.AddWithDuration("expired", 3, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	keys := tmap.Keys()
+	sort.Strings(keys)
+	if fmt.Sprint(keys) != "[a b]" {
+		t.Errorf("want: [a b], got: %v", keys)
+	}
+	values := tmap.Values()
+	sort.Ints(values)
+	if fmt.Sprint(values) != "[1 2]" {
+		t.Errorf("want: [1 2], got: %v", values)
+	}
+}
@@ -0,0 +1,181 @@
+package gocollections
+
+import (
+	"sync"
+	"time"
+)
+
+/*
+Map of Lists
+*/
+
+// MapOfLists is a map where every key holds a bounded list of expiring values in order of adding, ex. recent events
+// per user. When the list of the key is longer than maximum length, its oldest value is evicted. Keys are removed when
+// their lists become empty, so the map doesn't keep empty keys. Implementation of this map is running goroutine which
+// removes expired values. To stop this goroutine call Discard() method when this map is not needed any more.
+type MapOfLists[K comparable, V any] interface {
+	Add(key K, value V)
+	// Get returns unexpired values of the key in order of adding.
+	Get(key K) []V
+	// Len returns number of unexpired values of the key.
+	Len(key K) int
+	Del(key K) error
+	// Size returns number of keys with unexpired values.
+	Size() int
+	Discard()
+	ExpiredElChan() chan V
+	EvictedElChan() chan V
+}
+
+type mapOfLists[K comparable, V any] struct {
+	config      Config
+	listConfig  Config // config of lists of keys
+	mu          sync.Mutex
+	duration    time.Duration
+	data        map[K]*timeExpiredList[V]
+	expiredChan chan V
+	evictedChan chan V
+	quitChan    chan struct{}
+	clock       *coarseClock    // cached clock if Config.TimeResolution is set, shared by lists
+	suspend     suspendDetector // detects suspend of the process between cleaning runs
+	closed      bool
+}
+
+// NewMapOfLists creates new MapOfLists with duration of values and maximum length of list of the key. If maxLen is 0,
+// the lists are not bounded. Expired and evicted values are sent to channels of size Config.ExpiredElChanSize and
+// Config.EvictedElChanSize. Lists of keys are TimeExpiredLists without own goroutine, they are cleaned by the goroutine
+// of the map.
+func NewMapOfLists[K comparable, V any](duration time.Duration, maxLen int, configs ...Config) MapOfLists[K, V] {
+	config := newConfig(configs)
+	m := &mapOfLists[K, V]{
+		config: config,
+		listConfig: Config{
+			MaxLen:            maxLen,
+			ExpiredElChanSize: config.ExpiredElChanSize,
+			EvictedElChanSize: config.EvictedElChanSize,
+			ShrinkFactor:      config.ShrinkFactor,
+			WallClock:         config.WallClock,
+		},
+		duration:    duration,
+		data:        make(map[K]*timeExpiredList[V]),
+		expiredChan: make(chan V, config.ExpiredElChanSize),
+		evictedChan: make(chan V, config.EvictedElChanSize),
+		quitChan:    make(chan struct{}),
+		clock:       newCoarseClock(config),
+	}
+
+	startCleaner(config, m.quitChan, m.removeExpired)
+
+	return m
+}
+
+// Add appends value to the list of the key. If the list is longer than maximum length, its oldest value is evicted.
+func (m *mapOfLists[K, V]) Add(key K, value V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return
+	}
+	l, found := m.data[key]
+	if !found {
+		l = &timeExpiredList[V]{
+			config:      m.listConfig,
+			duration:    m.duration,
+			expiredChan: m.expiredChan,
+			evictedChan: m.evictedChan,
+			clock:       m.clock,
+		}
+		m.data[key] = l
+	}
+	l.Add(value)
+}
+
+func (m *mapOfLists[K, V]) Get(key K) []V {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if l, found := m.data[key]; found {
+		return l.GetAll()
+	}
+	return nil
+}
+
+func (m *mapOfLists[K, V]) Len(key K) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if l, found := m.data[key]; found {
+		return l.Size()
+	}
+	return 0
+}
+
+// Del removes the list of the key. It returns KeyError if the key has no unexpired value.
+func (m *mapOfLists[K, V]) Del(key K) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return ErrClosed
+	}
+	l, found := m.data[key]
+	if !found || l.Size() == 0 {
+		return &KeyError{Key: key}
+	}
+	delete(m.data, key)
+	return nil
+}
+
+func (m *mapOfLists[K, V]) Size() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	size := 0
+	for _, l := range m.data {
+		if l.Size() > 0 {
+			size++
+		}
+	}
+	return size
+}
+
+// Discard method stops the goroutine for removing expired values and discards data.
+func (m *mapOfLists[K, V]) Discard() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return
+	}
+	m.closed = true
+	close(m.quitChan)
+	m.clock.stop()
+	m.data = nil
+}
+
+// ExpiredElChan returns channel with expired values.
+func (m *mapOfLists[K, V]) ExpiredElChan() chan V {
+	return m.expiredChan
+}
+
+// EvictedElChan returns channel with values evicted by maximum length of the list.
+func (m *mapOfLists[K, V]) EvictedElChan() chan V {
+	return m.evictedChan
+}
+
+// removeExpired removes expired values from lists and keys with empty lists.
+func (m *mapOfLists[K, V]) removeExpired() {
+	m.suspend.handle(m.config, &m.mu, m.shiftExpirations)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, l := range m.data {
+		l.removeExpired()
+		if l.SizeRaw() == 0 {
+			delete(m.data, key)
+		}
+	}
+}
+
+// shiftExpirations moves expirations of values of all lists by d. It must be called with locked mutex.
+func (m *mapOfLists[K, V]) shiftExpirations(d time.Duration) {
+	for _, l := range m.data {
+		l.mu.Lock()
+		l.shiftExpirations(d)
+		l.mu.Unlock()
+	}
+}
@@ -0,0 +1,93 @@
+package gocollections
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestMapOfLists(t *testing.T) {
+	t.Parallel()
+
+	m := NewMapOfLists[string, int](time.Minute, 3, Config{EvictedElChanSize: 10})
+	defer m.Discard()
+	for i := 1; i <= 5; i++ {
+		m.Add("user1", i)
+	}
+	m.Add("user2", 10)
+
+	if got := fmt.Sprint(m.Get("user1")); got != "[3 4 5]" {
+		t.Errorf("want: [3 4 5], got: %s", got)
+	}
+	if evicted := len(m.EvictedElChan()); evicted != 2 {
+		t.Errorf("want: %d evicted, got: %d", 2, evicted)
+	}
+	if m.Size() != 2 || m.Len("user2") != 1 {
+		t.Errorf("unexpected size: %d, len: %d", m.Size(), m.Len("user2"))
+	}
+	if err := m.Del("user2"); err != nil {
+		t.Error(err)
+	}
+	if err := m.Del("user2"); err == nil {
+		t.Error("deleted key should not be found")
+	}
+}
+
+func TestMapOfLists_RemoveEmptyKeys(t *testing.T) {
+	t.Parallel()
+
+	m := NewMapOfLists[string, int](20*time.Millisecond, 0, Config{CleanJobInterval: 10 * time.Millisecond,
+		ExpiredElChanSize: 10})
+	defer m.Discard()
+	m.Add("user1", 1)
+	m.Add("user1", 2)
+
+	time.Sleep(50 * time.Millisecond)
+
+	impl := m.(*mapOfLists[string, int])
+	impl.mu.Lock()
+	keys := len(impl.data)
+	impl.mu.Unlock()
+	if keys != 0 {
+		t.Errorf("empty key should be removed, keys: %d", keys)
+	}
+	if expired := len(m.ExpiredElChan()); expired != 2 {
+		t.Errorf("want: %d expired, got: %d", 2, expired)
+	}
+}
+
+func TestMapOfLists_Evict(t *testing.T) {
+	t.Parallel()
+
+	m := NewMapOfLists[string, int](time.Minute, 2, Config{EvictedElChanSize: 10})
+	defer m.Discard()
+	for i := 1; i <= 4; i++ {
+		m.Add("user1", i)
+	}
+
+	if values := m.Get("user1"); fmt.Sprint(values) != "[3 4]" {
+		t.Errorf("want: [3 4], got: %v", values)
+	}
+	if evicted := len(m.EvictedElChan()); evicted != 2 {
+		t.Errorf("want: %d evicted, got: %d", 2, evicted)
+	}
+}
+
+func TestMapOfLists_Suspend(t *testing.T) {
+	t.Parallel()
+
+	lists := NewMapOfLists[string, int](NoExpiry, 0, Config{SuspendPolicy: SuspendExpire})
+	defer lists.Discard()
+	lists.Add("user1", 1)
+
+	// Simulate expiring of values lapsed during a minute of suspend.
+	m := lists.(*mapOfLists[string, int])
+	m.mu.Lock()
+	m.shiftExpirations(m.config.suspendShift(time.Minute))
+	m.mu.Unlock()
+	m.removeExpired()
+
+	if values := lists.Get("user1"); len(values) != 1 {
+		t.Errorf("Expect value without expiration kept, but got %v", values)
+	}
+}
@@ -0,0 +1,75 @@
+package gocollections
+
+import "time"
+
+/*
+Memoizer
+*/
+
+// Memoizer caches results of a function by key. Concurrent calls of the same key run the function only once.
+// Successful results are cached for ttl and errors for errTTL. It's built on TimeExpiredMap, so it's running goroutine
+// which removes expired results. To stop this goroutine call Discard() method when it's not needed any more.
+type Memoizer[K comparable, V any] interface {
+	Get(key K) (V, error)
+	Forget(key K)
+	Discard()
+}
+
+type memoResult[V any] struct {
+	value V
+	err   error
+}
+
+// memoCall is a running call of the function shared by concurrent callers of TimeExpiredMap.GetOrCompute.
+type memoCall[V any] struct {
+	done   chan struct{}
+	result memoResult[V]
+}
+
+type memoizer[K comparable, V any] struct {
+	fn      func(K) (V, error)
+	ttl     time.Duration
+	errTTL  time.Duration
+	results *timeExpiredMap[K, memoResult[V]]
+}
+
+// Memoize creates Memoizer of fn. Successful results are cached for ttl. Errors are cached for errTTL, if it's 0,
+// errors are not cached.
+func Memoize[K comparable, V any](fn func(K) (V, error), ttl, errTTL time.Duration, configs ...Config) Memoizer[K, V] {
+	return &memoizer[K, V]{
+		fn:      fn,
+		ttl:     ttl,
+		errTTL:  errTTL,
+		results: NewTimeExpiredMap[K, memoResult[V]](ttl, configs...).(*timeExpiredMap[K, memoResult[V]]),
+	}
+}
+
+// Get returns cached result of the key, or calls the function and caches its result. Concurrent calls share the call
+// by TimeExpiredMap.GetOrCompute.
+func (m *memoizer[K, V]) Get(key K) (V, error) {
+	r, err := m.results.compute(key, func() (memoResult[V], time.Duration, error) {
+		value, err := m.fn(key)
+		switch {
+		case err == nil:
+			return memoResult[V]{value: value}, m.ttl, nil
+		case m.errTTL > 0:
+			return memoResult[V]{value: value, err: err}, m.errTTL, nil
+		default:
+			return memoResult[V]{value: value}, 0, err
+		}
+	})
+	if err != nil {
+		return r.value, err
+	}
+	return r.value, r.err
+}
+
+// Forget removes cached result of the key.
+func (m *memoizer[K, V]) Forget(key K) {
+	_ = m.results.Del(key)
+}
+
+// Discard method stops the goroutine for removing expired results.
+func (m *memoizer[K, V]) Discard() {
+	m.results.Discard()
+}
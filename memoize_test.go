@@ -0,0 +1,75 @@
+package gocollections
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoize(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	errFailed := errors.New("failed")
+	m := Memoize(func(key string) (string, error) {
+		calls.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		if key == "bad" {
+			return "", errFailed
+		}
+		return "value " + key, nil
+	}, 10*time.Second, 0)
+	defer m.Discard()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if val, err := m.Get("1"); err != nil || val != "value 1" {
+				t.Errorf("want: %s, got: %s, %v", "value 1", val, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls.Load() != 1 {
+		t.Errorf("Expect function called once, but was called %d times", calls.Load())
+	}
+
+	// Errors are not cached with errTTL 0.
+	_, _ = m.Get("bad")
+	if _, err := m.Get("bad"); !errors.Is(err, errFailed) {
+		t.Errorf("Expect errFailed but got %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("Expect function called 3 times, but was called %d times", calls.Load())
+	}
+}
+
+func TestMemoize_Panic(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int64
+	memo := Memoize(func(key string) (int, error) {
+		if calls.Add(1) == 1 {
+			panic("boom")
+		}
+		return 42, nil
+	}, time.Minute, 0)
+	defer memo.Discard()
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expect panic of the function")
+			}
+		}()
+		_, _ = memo.Get("key")
+	}()
+
+	if v, err := memo.Get("key"); err != nil || v != 42 {
+		t.Errorf("want: %d, got: %d, %v", 42, v, err)
+	}
+}
@@ -0,0 +1,221 @@
+package gocollections
+
+import (
+	"sync"
+	"time"
+)
+
+/*
+Time Expired Multi Map
+*/
+
+// TimeExpiredMultiMap is a map with multiple values per key, where every value has its own expiration, ex. active
+// tokens or devices of a user. Implementation of this map is running goroutine which removes expired values. To stop
+// this goroutine call Discard() method when this map is not needed any more.
+type TimeExpiredMultiMap[K comparable, V any] interface {
+	Add(key K, value V)
+	AddWithDuration(key K, value V, duration time.Duration)
+	Get(key K) []V
+	Count(key K) int
+	Del(key K) error
+	DelFunc(key K, fn func(value V) bool) int
+	Size() int
+	Name() string
+	IsClosed() bool
+	Discard()
+	ExpiredElChan() chan V
+}
+
+type timeExpiredMultiMap[K comparable, V any] struct {
+	config      Config
+	mu          sync.Mutex
+	duration    time.Duration
+	data        map[K][]expiredElement[V]
+	expiredChan chan V
+	quitChan    chan struct{}
+	clock       *coarseClock    // cached clock if Config.TimeResolution is set
+	suspend     suspendDetector // detects suspend of the process between cleaning runs
+	closed      bool
+}
+
+// NewTimeExpiredMultiMap creates new TimeExpiredMultiMap with default duration of values.
+func NewTimeExpiredMultiMap[K comparable, V any](duration time.Duration, configs ...Config) TimeExpiredMultiMap[K, V] {
+	config := newConfig(configs)
+	m := &timeExpiredMultiMap[K, V]{
+		config:      config,
+		duration:    duration,
+		data:        make(map[K][]expiredElement[V]),
+		expiredChan: make(chan V, config.ExpiredElChanSize),
+		quitChan:    make(chan struct{}),
+		clock:       newCoarseClock(config),
+	}
+
+	startCleaner(config, m.quitChan, m.removeExpired)
+
+	if config.Register {
+		register(m, config)
+	}
+
+	return m
+}
+
+// Add adds value to the key with default duration.
+func (m *timeExpiredMultiMap[K, V]) Add(key K, value V) {
+	m.AddWithDuration(key, value, m.duration)
+}
+
+// AddWithDuration adds value to the key with duration. Other values of the key are kept.
+func (m *timeExpiredMultiMap[K, V]) AddWithDuration(key K, value V, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return
+	}
+	m.data[key] = append(m.data[key], expiredElement[V]{data: value, expiredAt: m.clock.expiry(duration)})
+}
+
+// Get returns unexpired values of the key in order of adding.
+func (m *timeExpiredMultiMap[K, V]) Get(key K) []V {
+	var result []V
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.clock.Now()
+	for _, e := range m.data[key] {
+		if e.expiredAt.After(now) {
+			result = append(result, e.data)
+		}
+	}
+	return result
+}
+
+// Count returns number of unexpired values of the key.
+func (m *timeExpiredMultiMap[K, V]) Count(key K) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.count(key, m.clock.Now())
+}
+
+// count returns number of unexpired values of the key. It must be called with locked mutex.
+func (m *timeExpiredMultiMap[K, V]) count(key K, now time.Time) int {
+	count := 0
+	for _, e := range m.data[key] {
+		if e.expiredAt.After(now) {
+			count++
+		}
+	}
+	return count
+}
+
+// Del removes all values of the key. It returns KeyError if the key has no unexpired value.
+func (m *timeExpiredMultiMap[K, V]) Del(key K) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return ErrClosed
+	}
+	if m.count(key, m.clock.Now()) == 0 {
+		return &KeyError{Key: key}
+	}
+	delete(m.data, key)
+	return nil
+}
+
+// DelFunc removes values of the key for which fn returns true. It returns number of removed values.
+func (m *timeExpiredMultiMap[K, V]) DelFunc(key K, fn func(value V) bool) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	values := m.data[key]
+	kept := values[:0]
+	for _, e := range values {
+		if !fn(e.data) {
+			kept = append(kept, e)
+		}
+	}
+	removed := len(values) - len(kept)
+	m.store(key, compact(kept, len(values), 0))
+	return removed
+}
+
+// store sets values of the key. Key without values is removed. It must be called with locked mutex.
+func (m *timeExpiredMultiMap[K, V]) store(key K, values []expiredElement[V]) {
+	if len(values) == 0 {
+		delete(m.data, key)
+		return
+	}
+	m.data[key] = values
+}
+
+// Size returns number of keys with unexpired values.
+func (m *timeExpiredMultiMap[K, V]) Size() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.clock.Now()
+	size := 0
+	for key := range m.data {
+		if m.count(key, now) > 0 {
+			size++
+		}
+	}
+	return size
+}
+
+// Name returns name of the map set in Config.
+func (m *timeExpiredMultiMap[K, V]) Name() string {
+	return m.config.Name
+}
+
+// IsClosed returns true if the map was discarded.
+func (m *timeExpiredMultiMap[K, V]) IsClosed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closed
+}
+
+// Discard method stops the goroutine for removing expired values and discards data.
+func (m *timeExpiredMultiMap[K, V]) Discard() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return
+	}
+	m.closed = true
+	close(m.quitChan)
+	m.clock.stop()
+	if m.config.Register {
+		unregister(m)
+	}
+	m.data = nil
+}
+
+// ExpiredElChan returns channel with expired values.
+func (m *timeExpiredMultiMap[K, V]) ExpiredElChan() chan V {
+	return m.expiredChan
+}
+
+// removeExpired removes expired values and sends them to expired element channel.
+func (m *timeExpiredMultiMap[K, V]) removeExpired() {
+	m.suspend.handle(m.config, &m.mu, m.shiftExpirations)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.clock.Now()
+	for key, values := range m.data {
+		kept := values[:0]
+		for _, e := range values {
+			if e.expiredAt.After(now) {
+				kept = append(kept, e)
+			} else {
+				sendDropOldest(m.expiredChan, e.data)
+			}
+		}
+		m.store(key, compact(kept, len(values), 0))
+	}
+}
+
+// shiftExpirations moves expirations of all values by d. It must be called with locked mutex.
+func (m *timeExpiredMultiMap[K, V]) shiftExpirations(d time.Duration) {
+	for _, values := range m.data {
+		for i := range values {
+			values[i].expiredAt = shiftExpiry(values[i].expiredAt, d)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package gocollections
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTimeExpiredMultiMap(t *testing.T) {
+	t.Parallel()
+
+	mmap := NewTimeExpiredMultiMap[string, string](10*time.Second, Config{CleanJobInterval: 10 * time.Millisecond})
+	defer mmap.Discard()
+
+	mmap.Add("user1", "token1")
+	mmap.AddWithDuration("user1", "token2", 20*time.Millisecond)
+	mmap.Add("user1", "token3")
+	mmap.Add("user2", "token4")
+
+	if values := mmap.Get("user1"); len(values) != 3 {
+		t.Errorf("Expect 3 values, but got %v", values)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if values := mmap.Get("user1"); len(values) != 2 || values[0] != "token1" || values[1] != "token3" {
+		t.Errorf("Expect values [token1 token3], but got %v", values)
+	}
+
+	if n := mmap.DelFunc("user1", func(value string) bool { return value == "token1" }); n != 1 {
+		t.Errorf("Expect 1 removed value, but got %d", n)
+	}
+	if err := mmap.Del("user2"); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if err := mmap.Del("user2"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Expect ErrKeyNotFound but got %v", err)
+	}
+	if mmap.Size() != 1 || mmap.Count("user1") != 1 {
+		t.Errorf("Expect 1 key with 1 value, but got %d keys and %d values", mmap.Size(), mmap.Count("user1"))
+	}
+}
+
+func TestTimeExpiredMultiMap_Suspend(t *testing.T) {
+	t.Parallel()
+
+	mmap := NewTimeExpiredMultiMap[string, string](10*time.Second, Config{SuspendPolicy: SuspendExpire})
+	defer mmap.Discard()
+	mmap.Add("user1", "token1")
+	mmap.AddWithDuration("user1", "token2", NoExpiry)
+
+	// Simulate expiring of values lapsed during a minute of suspend.
+	m := mmap.(*timeExpiredMultiMap[string, string])
+	m.mu.Lock()
+	m.shiftExpirations(m.config.suspendShift(time.Minute))
+	m.mu.Unlock()
+	m.removeExpired()
+
+	if values := mmap.Get("user1"); len(values) != 1 || values[0] != "token2" {
+		t.Errorf("Expect only value without expiration kept, but got %v", values)
+	}
+}
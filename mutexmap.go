@@ -0,0 +1,164 @@
+package gocollections
+
+import (
+	"sync"
+	"time"
+)
+
+/*
+Mutex Map
+*/
+
+// MutexMap is a map of mutexes per key, ex. lock per resource ID. Mutexes which are not used for idle duration are
+// removed, so the map doesn't grow without bound. Implementation of this map is running goroutine which removes idle
+// mutexes. To stop this goroutine call Discard() method when this map is not needed any more.
+type MutexMap[K comparable] interface {
+	Locker(key K) sync.Locker
+	Lock(key K)
+	Unlock(key K)
+	TryLock(key K) bool
+	Size() int
+	Discard()
+}
+
+type keyMutex struct {
+	mu       sync.Mutex
+	refs     int       // number of goroutines holding or waiting for the mutex
+	lastUsed time.Time // time of the last unlock
+}
+
+type mutexMap[K comparable] struct {
+	config   Config
+	mu       sync.Mutex
+	idle     time.Duration
+	data     map[K]*keyMutex
+	quitChan chan struct{}
+	clock    *coarseClock    // cached clock if Config.TimeResolution is set
+	suspend  suspendDetector // detects suspend of the process between cleaning runs
+	closed   bool            // true after Discard
+}
+
+// NewMutexMap creates new MutexMap which removes mutexes unused for idle duration. It runs goroutine for removing idle
+// mutexes.
+func NewMutexMap[K comparable](idle time.Duration, configs ...Config) MutexMap[K] {
+	config := newConfig(configs)
+	m := &mutexMap[K]{
+		config:   config,
+		idle:     idle,
+		data:     make(map[K]*keyMutex),
+		quitChan: make(chan struct{}),
+		clock:    newCoarseClock(config),
+	}
+
+	startCleaner(config, m.quitChan, m.removeIdle)
+
+	return m
+}
+
+// keyLocker is a sync.Locker of the key in MutexMap.
+type keyLocker[K comparable] struct {
+	m   *mutexMap[K]
+	key K
+}
+
+func (l keyLocker[K]) Lock() {
+	l.m.Lock(l.key)
+}
+
+func (l keyLocker[K]) Unlock() {
+	l.m.Unlock(l.key)
+}
+
+// Locker returns sync.Locker of the key. It remains valid even if the mutex of the key is removed as idle.
+func (m *mutexMap[K]) Locker(key K) sync.Locker {
+	return keyLocker[K]{m: m, key: key}
+}
+
+// Lock locks mutex of the key.
+func (m *mutexMap[K]) Lock(key K) {
+	m.acquire(key).mu.Lock()
+}
+
+// TryLock tries to lock mutex of the key and reports whether it succeeded.
+func (m *mutexMap[K]) TryLock(key K) bool {
+	km := m.acquire(key)
+	if km.mu.TryLock() {
+		return true
+	}
+	m.release(key, km)
+	return false
+}
+
+// Unlock unlocks mutex of the key. It panics if the mutex is not locked.
+func (m *mutexMap[K]) Unlock(key K) {
+	m.mu.Lock()
+	km, found := m.data[key]
+	m.mu.Unlock()
+	if !found {
+		panic("gocollections: unlock of unlocked key mutex")
+	}
+	km.mu.Unlock()
+	m.release(key, km)
+}
+
+// acquire returns mutex of the key and increments its references, so it's not removed while used.
+func (m *mutexMap[K]) acquire(key K) *keyMutex {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	km, found := m.data[key]
+	if !found {
+		km = &keyMutex{}
+		m.data[key] = km
+	}
+	km.refs++
+	return km
+}
+
+// release decrements references of the mutex.
+func (m *mutexMap[K]) release(key K, km *keyMutex) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	km.refs--
+	km.lastUsed = m.clock.Now()
+}
+
+// Size returns number of mutexes in the map.
+func (m *mutexMap[K]) Size() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.data)
+}
+
+// Discard method stops the goroutine for removing idle mutexes. Mutexes keep working after Discard, but idle mutexes
+// are not removed any more.
+func (m *mutexMap[K]) Discard() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return
+	}
+	m.closed = true
+	close(m.quitChan)
+	m.clock.stop()
+}
+
+// removeIdle removes mutexes which are not used and were unlocked before idle duration.
+func (m *mutexMap[K]) removeIdle() {
+	m.suspend.handle(m.config, &m.mu, m.shiftLastUsed)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	limit := m.clock.Now().Add(-m.idle)
+	for key, km := range m.data {
+		if km.refs == 0 && km.lastUsed.Before(limit) {
+			delete(m.data, key)
+		}
+	}
+}
+
+// shiftLastUsed moves the last use of all mutexes by d, so their idle removal is moved. It must be called with locked
+// mutex.
+func (m *mutexMap[K]) shiftLastUsed(d time.Duration) {
+	for _, km := range m.data {
+		km.lastUsed = km.lastUsed.Add(d)
+	}
+}
@@ -0,0 +1,82 @@
+package gocollections
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMutexMap(t *testing.T) {
+	t.Parallel()
+
+	mm := NewMutexMap[string](50*time.Millisecond, Config{
+		CleanJobInterval: 20 * time.Millisecond,
+	})
+	defer mm.Discard()
+
+	var counter int
+	var wg sync.WaitGroup
+	locker := mm.Locker("resource")
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			locker.Lock()
+			defer locker.Unlock()
+			counter++
+		}()
+	}
+	wg.Wait()
+	if counter != 100 {
+		t.Errorf("want: %d, got: %d", 100, counter)
+	}
+
+	mm.Lock("held")
+	if mm.TryLock("held") {
+		t.Error("TryLock of locked key should fail")
+	}
+	time.Sleep(150 * time.Millisecond)
+
+	// Idle mutex is removed, held mutex stays.
+	if mm.Size() != 1 {
+		t.Errorf("want: %d, got: %d", 1, mm.Size())
+	}
+	mm.Unlock("held")
+}
+
+func TestMutexMap_AfterDiscard(t *testing.T) {
+	t.Parallel()
+
+	mm := NewMutexMap[string](time.Minute)
+	mm.Discard()
+
+	mm.Lock("resource")
+	if mm.TryLock("resource") {
+		t.Error("Expect locked mutex after Discard")
+	}
+	mm.Unlock("resource")
+	if !mm.TryLock("resource") {
+		t.Error("Expect unlocked mutex after Discard")
+	}
+	mm.Unlock("resource")
+}
+
+func TestMutexMap_Suspend(t *testing.T) {
+	t.Parallel()
+
+	mm := NewMutexMap[string](10*time.Second, Config{SuspendPolicy: SuspendExpire})
+	defer mm.Discard()
+	mm.Lock("key1")
+	mm.Unlock("key1")
+
+	// Simulate idle time lapsed during a minute of suspend.
+	m := mm.(*mutexMap[string])
+	m.mu.Lock()
+	m.shiftLastUsed(m.config.suspendShift(time.Minute))
+	m.mu.Unlock()
+	m.removeIdle()
+
+	if size := mm.Size(); size != 0 {
+		t.Errorf("Expect idle mutex removed, but got size %d", size)
+	}
+}
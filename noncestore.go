@@ -0,0 +1,67 @@
+package gocollections
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"time"
+)
+
+/*
+Nonce Store
+*/
+
+// NonceStore is a store of one-time tokens, ex. CSRF tokens or OAuth nonces. Token can be consumed only once and it
+// expires after duration. Tokens are stored as SHA-256 hashes, so lookup time doesn't depend on the token value. It's
+// built on TimeExpiredMap, so it's running goroutine which removes expired tokens. To stop this goroutine call
+// Discard() method when this store is not needed any more.
+type NonceStore interface {
+	Add(token string)
+	Generate() (string, error)
+	ConsumeOnce(token string) bool
+	Size() int
+	Discard()
+}
+
+type nonceStore struct {
+	tokens TimeExpiredMap[[sha256.Size]byte, struct{}]
+}
+
+// NewNonceStore creates new NonceStore with tokens which expire after duration.
+func NewNonceStore(duration time.Duration, configs ...Config) NonceStore {
+	return &nonceStore{
+		tokens: NewTimeExpiredMap[[sha256.Size]byte, struct{}](duration, configs...),
+	}
+}
+
+// Add adds token to the store.
+func (s *nonceStore) Add(token string) {
+	s.tokens.Add(sha256.Sum256([]byte(token)), struct{}{})
+}
+
+// Generate creates new random token and adds it to the store.
+func (s *nonceStore) Generate() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	s.Add(token)
+	return token, nil
+}
+
+// ConsumeOnce returns true if the token is in the store and not expired, and removes it. Check and removal are
+// atomic, so the token can't be consumed twice.
+func (s *nonceStore) ConsumeOnce(token string) bool {
+	return s.tokens.Del(sha256.Sum256([]byte(token))) == nil
+}
+
+// Size returns number of unexpired tokens.
+func (s *nonceStore) Size() int {
+	return s.tokens.Size()
+}
+
+// Discard method stops the goroutine for removing expired tokens and discards all tokens.
+func (s *nonceStore) Discard() {
+	s.tokens.Discard()
+}
@@ -0,0 +1,40 @@
+package gocollections
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNonceStore(t *testing.T) {
+	t.Parallel()
+
+	store := NewNonceStore(10 * time.Second)
+	defer store.Discard()
+
+	token, err := store.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var consumed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if store.ConsumeOnce(token) {
+				consumed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if consumed.Load() != 1 {
+		t.Errorf("Expect token consumed once, but was consumed %d times", consumed.Load())
+	}
+	if store.ConsumeOnce("unknown") {
+		t.Error("Unknown token should not be consumed")
+	}
+}
@@ -0,0 +1,227 @@
+package gocollections
+
+import (
+	"sync"
+	"time"
+)
+
+/*
+Owner Map
+*/
+
+// OwnerMap is a map of elements owned by registered owners, like ephemeral nodes. Elements of the owner expire when
+// the owner is closed or misses its heartbeat. Expired elements are sent to expired channel. Implementation of this
+// map is running goroutine which removes expired owners. To stop this goroutine call Discard() method when this map is
+// not needed any more.
+type OwnerMap[K comparable, V any] interface {
+	Register(ownerID string, heartbeatTTL time.Duration) (Owner[K, V], error)
+	Get(key K) (V, error)
+	Size() int
+	Discard()
+	ExpiredElChan() chan V
+}
+
+// Owner is a registered owner of OwnerMap elements.
+type Owner[K comparable, V any] interface {
+	ID() string
+	// Add adds element owned by the owner. It returns ErrOwnerNotFound if the owner is closed or expired.
+	Add(key K, value V) error
+	// Heartbeat extends the owner by its heartbeat TTL. It returns ErrOwnerNotFound if the owner is closed or expired.
+	Heartbeat() error
+	// Close expires the owner and its elements.
+	Close()
+}
+
+type ownedValue[V any] struct {
+	owner *owner[V]
+	value V
+}
+
+type owner[V any] struct {
+	id        string
+	ttl       time.Duration
+	expiredAt time.Time
+	closed    bool
+}
+
+type ownerMap[K comparable, V any] struct {
+	config      Config
+	mu          sync.Mutex
+	data        map[K]ownedValue[V]
+	owners      map[string]*owner[V]
+	keys        map[*owner[V]]map[K]struct{} // keys of the owner
+	expiredChan chan V
+	quitChan    chan struct{}
+	clock       *coarseClock    // cached clock if Config.TimeResolution is set
+	suspend     suspendDetector // detects suspend of the process between cleaning runs
+	closed      bool
+}
+
+// NewOwnerMap creates new OwnerMap. Expired elements are sent to channel with size Config.ExpiredElChanSize.
+func NewOwnerMap[K comparable, V any](configs ...Config) OwnerMap[K, V] {
+	config := newConfig(configs)
+	m := &ownerMap[K, V]{
+		config:      config,
+		data:        make(map[K]ownedValue[V]),
+		owners:      make(map[string]*owner[V]),
+		keys:        make(map[*owner[V]]map[K]struct{}),
+		expiredChan: make(chan V, config.ExpiredElChanSize),
+		quitChan:    make(chan struct{}),
+		clock:       newCoarseClock(config),
+	}
+
+	startCleaner(config, m.quitChan, m.removeExpired)
+
+	return m
+}
+
+// Register registers owner with heartbeatTTL. It returns ErrOwnerExists if the owner is already registered and not
+// expired.
+func (m *ownerMap[K, V]) Register(ownerID string, heartbeatTTL time.Duration) (Owner[K, V], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil, ErrClosed
+	}
+	now := m.clock.Now()
+	if o, found := m.owners[ownerID]; found {
+		if o.alive(now) {
+			return nil, ErrOwnerExists
+		}
+		m.expireOwner(o)
+	}
+	o := &owner[V]{id: ownerID, ttl: heartbeatTTL, expiredAt: m.clock.expiry(heartbeatTTL)}
+	m.owners[ownerID] = o
+	m.keys[o] = make(map[K]struct{})
+	return &ownerHandle[K, V]{m: m, o: o}, nil
+}
+
+// Get returns element by key. It returns KeyError if the key is not in the map or its owner expired.
+func (m *ownerMap[K, V]) Get(key K) (V, error) {
+	var zero V
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return zero, ErrClosed
+	}
+	v, found := m.data[key]
+	if !found || !v.owner.alive(m.clock.Now()) {
+		return zero, &KeyError{Key: key}
+	}
+	return v.value, nil
+}
+
+// Size returns number of elements of unexpired owners.
+func (m *ownerMap[K, V]) Size() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.clock.Now()
+	size := 0
+	for o, keys := range m.keys {
+		if o.alive(now) {
+			size += len(keys)
+		}
+	}
+	return size
+}
+
+// Discard method stops the goroutine for removing expired owners and discards data.
+func (m *ownerMap[K, V]) Discard() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return
+	}
+	m.closed = true
+	close(m.quitChan)
+	m.clock.stop()
+	m.data = nil
+	m.owners = nil
+	m.keys = nil
+}
+
+// ExpiredElChan returns channel with elements of expired owners.
+func (m *ownerMap[K, V]) ExpiredElChan() chan V {
+	return m.expiredChan
+}
+
+// removeExpired removes owners which missed heartbeat with their elements.
+func (m *ownerMap[K, V]) removeExpired() {
+	m.suspend.handle(m.config, &m.mu, m.shiftExpirations)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.clock.Now()
+	for _, o := range m.owners {
+		if !o.alive(now) {
+			m.expireOwner(o)
+		}
+	}
+}
+
+// shiftExpirations moves expirations of all owners by d. It must be called with locked mutex.
+func (m *ownerMap[K, V]) shiftExpirations(d time.Duration) {
+	for _, o := range m.owners {
+		o.expiredAt = shiftExpiry(o.expiredAt, d)
+	}
+}
+
+// expireOwner removes the owner and sends its elements to expired channel. It must be called with locked mutex.
+func (m *ownerMap[K, V]) expireOwner(o *owner[V]) {
+	o.closed = true
+	for key := range m.keys[o] {
+		sendDropOldest(m.expiredChan, m.data[key].value)
+		delete(m.data, key)
+	}
+	delete(m.keys, o)
+	if m.owners[o.id] == o {
+		delete(m.owners, o.id)
+	}
+}
+
+// alive returns true if the owner is not closed and not expired.
+func (o *owner[V]) alive(now time.Time) bool {
+	return !o.closed && o.expiredAt.After(now)
+}
+
+type ownerHandle[K comparable, V any] struct {
+	m *ownerMap[K, V]
+	o *owner[V]
+}
+
+func (h *ownerHandle[K, V]) ID() string {
+	return h.o.id
+}
+
+func (h *ownerHandle[K, V]) Add(key K, value V) error {
+	h.m.mu.Lock()
+	defer h.m.mu.Unlock()
+	if h.m.closed || !h.o.alive(h.m.clock.Now()) {
+		return ErrOwnerNotFound
+	}
+	// Key of another owner is moved to this owner.
+	if v, found := h.m.data[key]; found {
+		delete(h.m.keys[v.owner], key)
+	}
+	h.m.data[key] = ownedValue[V]{owner: h.o, value: value}
+	h.m.keys[h.o][key] = struct{}{}
+	return nil
+}
+
+func (h *ownerHandle[K, V]) Heartbeat() error {
+	h.m.mu.Lock()
+	defer h.m.mu.Unlock()
+	if h.m.closed || !h.o.alive(h.m.clock.Now()) {
+		return ErrOwnerNotFound
+	}
+	h.o.expiredAt = h.m.clock.expiry(h.o.ttl)
+	return nil
+}
+
+func (h *ownerHandle[K, V]) Close() {
+	h.m.mu.Lock()
+	defer h.m.mu.Unlock()
+	if h.m.closed || h.o.closed {
+		return
+	}
+	h.m.expireOwner(h.o)
+}
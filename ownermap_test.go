@@ -0,0 +1,67 @@
+package gocollections
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOwnerMap(t *testing.T) {
+	t.Parallel()
+
+	omap := NewOwnerMap[string, string](Config{CleanJobInterval: 10 * time.Millisecond, ExpiredElChanSize: 10})
+	defer omap.Discard()
+
+	o1, err := omap.Register("node1", 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	o2, _ := omap.Register("node2", 10*time.Second)
+	if _, err := omap.Register("node1", time.Second); !errors.Is(err, ErrOwnerExists) {
+		t.Errorf("Expect ErrOwnerExists but got %v", err)
+	}
+
+	_ = o1.Add("key1", "value1")
+	_ = o2.Add("key2", "value2")
+	_ = o2.Add("key3", "value3")
+
+	// node1 misses heartbeat.
+	time.Sleep(100 * time.Millisecond)
+	if _, err := omap.Get("key1"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Expect ErrKeyNotFound but got %v", err)
+	}
+	if err := o1.Heartbeat(); !errors.Is(err, ErrOwnerNotFound) {
+		t.Errorf("Expect ErrOwnerNotFound but got %v", err)
+	}
+	if val := <-omap.ExpiredElChan(); val != "value1" {
+		t.Errorf("Expect expired value1, but got %s", val)
+	}
+
+	o2.Close()
+	if omap.Size() != 0 {
+		t.Errorf("Expect empty map after owner is closed, but size is %d", omap.Size())
+	}
+}
+
+func TestOwnerMap_Suspend(t *testing.T) {
+	t.Parallel()
+
+	omap := NewOwnerMap[string, string](Config{SuspendPolicy: SuspendExpire, ExpiredElChanSize: 10})
+	defer omap.Discard()
+	o, _ := omap.Register("node1", 10*time.Second)
+	_ = o.Add("key1", "value1")
+
+	// Simulate expiring of owners which missed heartbeat during a minute of suspend.
+	m := omap.(*ownerMap[string, string])
+	m.mu.Lock()
+	m.shiftExpirations(m.config.suspendShift(time.Minute))
+	m.mu.Unlock()
+	m.removeExpired()
+
+	if err := o.Heartbeat(); !errors.Is(err, ErrOwnerNotFound) {
+		t.Errorf("Expect ErrOwnerNotFound, but got %v", err)
+	}
+	if val := <-omap.ExpiredElChan(); val != "value1" {
+		t.Errorf("Expect expired value1, but got %s", val)
+	}
+}
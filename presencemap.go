@@ -0,0 +1,151 @@
+package gocollections
+
+import (
+	"sync"
+	"time"
+)
+
+/*
+Presence Map
+*/
+
+// PresenceMap tracks which keys are online, ex. connected users. Key is online while it sends heartbeats within TTL.
+// When the key misses heartbeats, it goes offline and the onOffline callback is called. Implementation of this map is
+// running goroutine which detects offline keys, so the callback is called with delay up to Config.CleanJobInterval, or
+// by the next heartbeat of the key if it comes before. To stop this goroutine call Discard() method when this map is
+// not needed any more.
+type PresenceMap[K comparable] interface {
+	Heartbeat(key K) bool
+	IsOnline(key K) bool
+	Online() []K
+	Size() int
+	Discard()
+}
+
+type presenceMap[K comparable] struct {
+	config    Config
+	mu        sync.Mutex
+	ttl       time.Duration
+	onOffline func(key K)
+	data      map[K]time.Time // expiration of the key presence
+	quitChan  chan struct{}
+	clock     *coarseClock    // cached clock if Config.TimeResolution is set
+	suspend   suspendDetector // detects suspend of the process between cleaning runs
+	closed    bool
+}
+
+// NewPresenceMap creates new PresenceMap. Keys go offline if they miss heartbeats for ttl. Function onOffline is called
+// with every key which went offline, it can be nil.
+func NewPresenceMap[K comparable](ttl time.Duration, onOffline func(key K), configs ...Config) PresenceMap[K] {
+	config := newConfig(configs)
+	m := &presenceMap[K]{
+		config:    config,
+		ttl:       ttl,
+		onOffline: onOffline,
+		data:      make(map[K]time.Time),
+		quitChan:  make(chan struct{}),
+		clock:     newCoarseClock(config),
+	}
+
+	startCleaner(config, m.quitChan, m.removeOffline)
+
+	return m
+}
+
+// Heartbeat refreshes presence of the key. It returns true if the key was offline before. If the key missed heartbeats
+// and it's not detected yet, the onOffline callback is called before the key comes online again.
+func (m *presenceMap[K]) Heartbeat(key K) bool {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return false
+	}
+	expiredAt, found := m.data[key]
+	missed := found && expiredAt.Before(m.clock.Now())
+	m.data[key] = m.clock.expiry(m.ttl)
+	m.mu.Unlock()
+
+	if missed && m.onOffline != nil {
+		m.onOffline(key)
+	}
+	return !found || missed
+}
+
+// IsOnline returns true if the key sent heartbeat within TTL.
+func (m *presenceMap[K]) IsOnline(key K) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	expiredAt, found := m.data[key]
+	return found && expiredAt.After(m.clock.Now())
+}
+
+// Online returns online keys.
+func (m *presenceMap[K]) Online() []K {
+	var result []K
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.clock.Now()
+	for key, expiredAt := range m.data {
+		if expiredAt.After(now) {
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
+// Size returns number of online keys.
+func (m *presenceMap[K]) Size() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.clock.Now()
+	size := 0
+	for _, expiredAt := range m.data {
+		if expiredAt.After(now) {
+			size++
+		}
+	}
+	return size
+}
+
+// Discard method stops the goroutine for detecting offline keys and discards data. The onOffline callback is not
+// called for keys online at the time of Discard.
+func (m *presenceMap[K]) Discard() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return
+	}
+	m.closed = true
+	close(m.quitChan)
+	m.clock.stop()
+	m.data = nil
+}
+
+// removeOffline removes keys which missed heartbeats and calls onOffline callback outside of the lock.
+func (m *presenceMap[K]) removeOffline() {
+	m.suspend.handle(m.config, &m.mu, m.shiftExpirations)
+	var offline []K
+	m.mu.Lock()
+	now := m.clock.Now()
+	for key, expiredAt := range m.data {
+		if expiredAt.Before(now) {
+			delete(m.data, key)
+			offline = append(offline, key)
+		}
+	}
+	m.mu.Unlock()
+
+	if m.onOffline == nil {
+		return
+	}
+	for _, key := range offline {
+		m.onOffline(key)
+	}
+}
+
+// shiftExpirations moves expirations of presence of all keys by d. It must be called with locked mutex.
+func (m *presenceMap[K]) shiftExpirations(d time.Duration) {
+	for key, expiredAt := range m.data {
+		m.data[key] = shiftExpiry(expiredAt, d)
+	}
+}
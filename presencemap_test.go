@@ -0,0 +1,60 @@
+package gocollections
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPresenceMap(t *testing.T) {
+	t.Parallel()
+
+	offline := make(chan string, 10)
+	pmap := NewPresenceMap[string](50*time.Millisecond, func(key string) {
+		offline <- key
+	}, Config{CleanJobInterval: 10 * time.Millisecond})
+	defer pmap.Discard()
+
+	if !pmap.Heartbeat("user1") {
+		t.Error("Expect user1 came online")
+	}
+	pmap.Heartbeat("user2")
+	for i := 0; i < 5; i++ {
+		time.Sleep(20 * time.Millisecond)
+		if pmap.Heartbeat("user2") {
+			t.Error("Expect user2 stays online")
+		}
+	}
+
+	select {
+	case key := <-offline:
+		if key != "user1" {
+			t.Errorf("Expect user1 offline, but got %s", key)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expect user1 offline")
+	}
+	if pmap.IsOnline("user1") || !pmap.IsOnline("user2") || pmap.Size() != 1 {
+		t.Errorf("Expect only user2 online, but got %v", pmap.Online())
+	}
+}
+
+func TestPresenceMap_HeartbeatAfterMissedTTL(t *testing.T) {
+	t.Parallel()
+
+	var offline []string
+	pmap := NewPresenceMap[string](20*time.Millisecond, func(key string) {
+		offline = append(offline, key)
+	})
+	defer pmap.Discard()
+
+	pmap.Heartbeat("user1")
+	time.Sleep(50 * time.Millisecond)
+
+	// The cleaner doesn't run before the next heartbeat, which reports the missed one.
+	if !pmap.Heartbeat("user1") {
+		t.Error("Expect user1 came online again")
+	}
+	if len(offline) != 1 || offline[0] != "user1" {
+		t.Errorf("Expect user1 offline before it came online, but got %v", offline)
+	}
+}
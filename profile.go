@@ -0,0 +1,39 @@
+package gocollections
+
+import "time"
+
+/*
+Config profiles
+*/
+
+// ProfileSessionStore returns Config for sessions or tokens which live minutes to hours. Expired sessions are sent to
+// expired element channel, ex. for logout hooks, and the cleaner runs often enough to drop them soon after expiration.
+func ProfileSessionStore() Config {
+	return Config{
+		CleanJobInterval:  30 * time.Second,
+		ExpiredElChanSize: 100,
+	}
+}
+
+// ProfileHighChurnQueue returns Config for short-lived elements added and expired at a high rate, ex. work queues.
+// The cleaner runs every second, expirations use cached clock with 10ms resolution and storage is released early.
+func ProfileHighChurnQueue() Config {
+	return Config{
+		CleanJobInterval:  time.Second,
+		ExpiredElChanSize: 1000,
+		EvictedElChanSize: 1000,
+		TimeResolution:    10 * time.Millisecond,
+		ShrinkFactor:      2,
+	}
+}
+
+// ProfileLargeReadMostlyCache returns Config for big caches with long TTL which are mostly read. The cleaner runs
+// rarely, expired elements are not sent to channel and the map is rebuilt only after many removals. Set GroupFn and
+// GroupMaxLen of the returned Config to limit its size, ex. GroupFn returning one group limits size of the whole map.
+func ProfileLargeReadMostlyCache() Config {
+	return Config{
+		CleanJobInterval: 5 * time.Minute,
+		TimeResolution:   time.Second,
+		ShrinkFactor:     8,
+	}
+}
@@ -0,0 +1,20 @@
+package gocollections
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProfiles(t *testing.T) {
+	t.Parallel()
+
+	for _, config := range []Config{ProfileSessionStore(), ProfileHighChurnQueue(), ProfileLargeReadMostlyCache()} {
+		config.Name = "profile"
+		tmap := NewTimeExpiredMap[string, string](time.Minute, config)
+		tmap.Add("key1", "value1")
+		if v, err := tmap.Get("key1"); err != nil || v != "value1" {
+			t.Errorf("%v: want: %s, got: %s, %v", config, "value1", v, err)
+		}
+		tmap.Discard()
+	}
+}
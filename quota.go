@@ -0,0 +1,94 @@
+package gocollections
+
+/*
+Quota groups of the map
+*/
+
+// GroupStats is statistic of the quota group of the map.
+type GroupStats struct {
+	Size    int // number of elements in the group, including expired elements which are not removed yet
+	Evicted int // number of elements evicted because the group was full
+}
+
+// groupEvict evicts the element of the quota group of new key which expires first, if the group is full. It must be
+// called with locked mutex before set changes keys or data of the map, because eviction removes another element.
+func (m *timeExpiredMap[K, V]) groupEvict(key K) {
+	if m.config.GroupFn == nil || m.config.GroupMaxLen <= 0 {
+		return
+	}
+	if _, found := m.data[key]; found {
+		return
+	}
+	group := m.config.GroupFn(key)
+	if m.groups[group].Size < m.config.GroupMaxLen {
+		return
+	}
+	m.evictFromGroup(group)
+	stats := m.groups[group]
+	stats.Evicted++
+	m.groups[group] = stats
+}
+
+// groupAdd counts new key in its quota group. It must be called with locked mutex after the key is stored.
+func (m *timeExpiredMap[K, V]) groupAdd(key K) {
+	if m.config.GroupFn == nil {
+		return
+	}
+	group := m.config.GroupFn(key)
+	stats := m.groups[group]
+	stats.Size++
+	m.groups[group] = stats
+	if m.groupKeys == nil {
+		m.groupKeys = make(map[string]map[K]struct{})
+	}
+	keys, found := m.groupKeys[group]
+	if !found {
+		keys = make(map[K]struct{})
+		m.groupKeys[group] = keys
+	}
+	keys[key] = struct{}{}
+}
+
+// groupRemove removes key from its quota group. It must be called with locked mutex.
+func (m *timeExpiredMap[K, V]) groupRemove(key K) {
+	group := m.config.GroupFn(key)
+	stats := m.groups[group]
+	stats.Size--
+	m.groups[group] = stats
+	keys := m.groupKeys[group]
+	delete(keys, key)
+	if len(keys) == 0 {
+		delete(m.groupKeys, group)
+	}
+}
+
+// evictFromGroup removes element of the group which expires first and sends it to evicted element channel. It must be
+// called with locked mutex.
+func (m *timeExpiredMap[K, V]) evictFromGroup(group string) {
+	var (
+		victim K
+		first  expiredElement[V]
+		found  bool
+	)
+	for key := range m.groupKeys[group] {
+		if e := m.data[key]; !found || e.expiredAt.Before(first.expiredAt) {
+			victim, first, found = key, e, true
+		}
+	}
+	if found {
+		m.remove(victim)
+		sendDropOldest(m.evictedChan, first.data)
+	}
+}
+
+// GroupStats returns statistics of quota groups defined by Config.GroupFn. Groups stay in statistics when they become
+// empty.
+func (m *timeExpiredMap[K, V]) GroupStats() map[string]GroupStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make(map[string]GroupStats, len(m.groups))
+	for group, stats := range m.groups {
+		result[group] = stats
+	}
+	return result
+}
@@ -0,0 +1,72 @@
+package gocollections
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeExpiredMap_GroupMaxLen(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](10*time.Second, Config{
+		GroupFn: func(key any) string {
+			return strings.Split(key.(string), ":")[0]
+		},
+		GroupMaxLen:       2,
+		EvictedElChanSize: 10,
+	})
+	defer tmap.Discard()
+
+	tmap.AddWithDuration("a:1", "value1", time.Second)
+	tmap.Add("a:2", "value2")
+	tmap.Add("b:1", "value1")
+	tmap.Add("a:3", "value3")
+
+	if tmap.Contains("a:1") {
+		t.Error("Expect a:1 evicted as it expires first in full group")
+	}
+	if !tmap.Contains("b:1") {
+		t.Error("Expect element of another group kept")
+	}
+	stats := tmap.GroupStats()
+	if stats["a"].Size != 2 || stats["a"].Evicted != 1 || stats["b"].Size != 1 {
+		t.Errorf("Unexpected group stats %v", stats)
+	}
+	select {
+	case val := <-tmap.EvictedElChan():
+		if val != "value1" {
+			t.Errorf("Expect evicted value1, but got %s", val)
+		}
+	default:
+		t.Error("Expect evicted element in evicted channel")
+	}
+
+	// Removed keys leave the group index, so the next eviction picks a present element.
+	_ = tmap.Del("a:2")
+	tmap.Add("a:4", "value4")
+	tmap.Add("a:5", "value5")
+	if !tmap.Contains("a:4") || !tmap.Contains("a:5") || tmap.Contains("a:3") {
+		t.Errorf("Expect a:3 evicted, but got %v", tmap.Keys())
+	}
+
+	// Eviction keeps the map consistent for other operations.
+	keys := tmap.SampleKeys(10)
+	sort.Strings(keys)
+	if fmt.Sprint(keys) != "[a:4 a:5 b:1]" {
+		t.Errorf("want: [a:4 a:5 b:1], got: %v", keys)
+	}
+	if size := tmap.Size(); size != 3 {
+		t.Errorf("want: size 3, got: %d", size)
+	}
+	for _, key := range []string{"a:4", "a:5", "b:1"} {
+		if err := tmap.Del(key); err != nil {
+			t.Errorf("del %s: %v", key, err)
+		}
+	}
+	if size := tmap.Size(); size != 0 {
+		t.Errorf("want: size 0, got: %d", size)
+	}
+}
@@ -0,0 +1,59 @@
+package gocollections
+
+import "time"
+
+/*
+Refresh-ahead
+*/
+
+// refresher renews element added by AddWithRefresher.
+type refresher[K comparable, V any] struct {
+	fn       func(key K, value V) (V, error)
+	duration time.Duration
+	running  bool // true while fn is called
+}
+
+// AddWithRefresher adds element to the map with duration and function which renews it. The cleaning goroutine calls
+// refresh in new goroutine for elements which would expire before the next cleaning run or within
+// Config.AboutToExpireLead. Returned value replaces the element with the same duration and refresher. If refresh
+// returns error, then the element expires as usual. Add of the key without refresher removes the refresher.
+func (m *timeExpiredMap[K, V]) AddWithRefresher(key K, data V, duration time.Duration,
+	refresh func(key K, value V) (V, error)) {
+	duration, err := m.prepareAdd(key, data, duration)
+	if err != nil {
+		return
+	}
+	defer m.mu.Unlock()
+	m.set(key, data, duration)
+	if m.refreshers == nil {
+		m.refreshers = make(map[K]*refresher[K, V])
+	}
+	m.refreshers[key] = &refresher[K, V]{fn: refresh, duration: duration}
+}
+
+// startRefreshes starts refresh of elements which expire before deadline. It must be called with locked mutex.
+func (m *timeExpiredMap[K, V]) startRefreshes(now time.Time) {
+	deadline := now.Add(m.config.CleanJobInterval + m.config.AboutToExpireLead)
+	for key, r := range m.refreshers {
+		e := m.data[key]
+		if r.running || e.expiredAt.Before(now) || e.expiredAt.After(deadline) {
+			continue
+		}
+		r.running = true
+		go m.refresh(key, e.data, r)
+	}
+}
+
+// refresh calls refresher of the element and replaces the element by returned value.
+func (m *timeExpiredMap[K, V]) refresh(key K, value V, r *refresher[K, V]) {
+	value, err := r.fn(key, value)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r.running = false
+	// The element was replaced, removed or expired during refresh.
+	if err != nil || m.closed || m.refreshers[key] != r {
+		return
+	}
+	m.set(key, value, r.duration)
+	m.refreshers[key] = r
+}
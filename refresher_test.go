@@ -0,0 +1,29 @@
+package gocollections
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTimeExpiredMap_AddWithRefresher(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, int](time.Minute, Config{CleanJobInterval: 20 * time.Millisecond})
+	defer tmap.Discard()
+	tmap.AddWithRefresher("refreshed", 0, 30*time.Millisecond, func(key string, value int) (int, error) {
+		return value + 1, nil
+	})
+	tmap.AddWithRefresher("failing", 0, 30*time.Millisecond, func(key string, value int) (int, error) {
+		return 0, errors.New("load failed")
+	})
+
+	time.Sleep(150 * time.Millisecond)
+
+	if v, err := tmap.Get("refreshed"); err != nil || v < 1 {
+		t.Errorf("element should be refreshed, got: %d, %v", v, err)
+	}
+	if tmap.Contains("failing") {
+		t.Error("element with failing refresher should expire")
+	}
+}
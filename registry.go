@@ -0,0 +1,80 @@
+package gocollections
+
+import (
+	"sort"
+	"sync"
+)
+
+/*
+Registry of live collections
+*/
+
+// Collection is an interface implemented by all collections which can be in the registry.
+type Collection interface {
+	Name() string
+	Size() int
+	Discard()
+	IsClosed() bool
+}
+
+// CollectionInfo describes collection in the registry.
+type CollectionInfo struct {
+	Name       string
+	Size       int
+	Config     Config
+	Collection Collection
+}
+
+var registry = struct {
+	mu          sync.Mutex
+	collections map[Collection]Config
+}{
+	collections: make(map[Collection]Config),
+}
+
+// register adds collection to the registry.
+func register(c Collection, config Config) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.collections[c] = config
+}
+
+// unregister removes collection from the registry.
+func unregister(c Collection) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	delete(registry.collections, c)
+}
+
+// Registered returns info about all active collections created with Config.Register, sorted by name.
+func Registered() []CollectionInfo {
+	registry.mu.Lock()
+	var result []CollectionInfo
+	for c, config := range registry.collections {
+		result = append(result, CollectionInfo{Name: config.Name, Config: config, Collection: c})
+	}
+	registry.mu.Unlock()
+
+	// Size is read out of the registry lock, because collections lock itself.
+	for i := range result {
+		result[i].Size = result[i].Collection.Size()
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// DiscardAll discards all collections in the registry.
+func DiscardAll() {
+	registry.mu.Lock()
+	var collections []Collection
+	for c := range registry.collections {
+		collections = append(collections, c)
+	}
+	registry.mu.Unlock()
+
+	for _, c := range collections {
+		c.Discard()
+	}
+}
@@ -0,0 +1,39 @@
+package gocollections
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRegistry(t *testing.T) {
+	tmap := NewTimeExpiredMap[string, string](10*time.Second, Config{Name: "registry-map", Register: true})
+	tlist := NewTimeExpiredList[string](10*time.Second, Config{Name: "registry-list", Register: true})
+	defer tlist.Discard()
+	defer tmap.Discard()
+
+	tmap.Add("1", "test 1")
+
+	var found int
+	for _, info := range Registered() {
+		switch info.Name {
+		case "registry-map":
+			found++
+			if info.Size != 1 {
+				t.Errorf("want: %d, got: %d", 1, info.Size)
+			}
+		case "registry-list":
+			found++
+		}
+	}
+	if found != 2 {
+		t.Errorf("Expect 2 registered collections, but found %d", found)
+	}
+
+	DiscardAll()
+	if !tmap.IsClosed() || !tlist.IsClosed() {
+		t.Error("collections should be closed after DiscardAll")
+	}
+	if len(Registered()) != 0 {
+		t.Errorf("Expect empty registry, but got %d collections", len(Registered()))
+	}
+}
@@ -0,0 +1,163 @@
+package gocollections
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+/*
+Request keys
+*/
+
+// RequestKey returns stable cache key of request, ex. struct with parameters of a function cached by Memoize. The key
+// is hash of exported fields of the request, so requests with the same field values have the same key regardless of
+// map iteration order or pointers. Fields with tag `cache:"-"` are excluded, ex. trace ids. Values implementing
+// encoding.BinaryMarshaler or encoding.TextMarshaler, ex. time.Time, are encoded by their marshaler. It returns error
+// if the request contains function, channel or struct with only unexported fields, which state would be ignored.
+// Request must not contain cyclic pointers.
+func RequestKey(req any) (string, error) {
+	var buf bytes.Buffer
+	if err := encodeKey(&buf, reflect.ValueOf(req)); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// encodeKey writes canonical encoding of v to buf. Every value starts with its kind, so values of different kinds
+// don't have the same encoding.
+func encodeKey(buf *bytes.Buffer, v reflect.Value) error {
+	if !v.IsValid() {
+		buf.WriteByte(0)
+		return nil
+	}
+	buf.WriteByte(byte(v.Kind()))
+	if ok, err := encodeMarshaler(buf, v); ok || err != nil {
+		return err
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			buf.WriteByte(1)
+		} else {
+			buf.WriteByte(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint(buf, uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint(buf, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeUint(buf, math.Float64bits(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		writeUint(buf, math.Float64bits(real(v.Complex())))
+		writeUint(buf, math.Float64bits(imag(v.Complex())))
+	case reflect.String:
+		writeString(buf, v.String())
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			buf.WriteByte(0)
+			return nil
+		}
+		buf.WriteByte(1)
+		if v.Kind() == reflect.Interface {
+			writeString(buf, v.Elem().Type().String())
+		}
+		return encodeKey(buf, v.Elem())
+	case reflect.Slice, reflect.Array:
+		writeUint(buf, uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			if err := encodeKey(buf, v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		// Entries are encoded separately and sorted, so the encoding doesn't depend on iteration order.
+		entries := make([][]byte, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			var entry bytes.Buffer
+			if err := encodeKey(&entry, iter.Key()); err != nil {
+				return err
+			}
+			if err := encodeKey(&entry, iter.Value()); err != nil {
+				return err
+			}
+			entries = append(entries, entry.Bytes())
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			return bytes.Compare(entries[i], entries[j]) < 0
+		})
+		writeUint(buf, uint64(len(entries)))
+		for _, entry := range entries {
+			writeString(buf, string(entry))
+		}
+	case reflect.Struct:
+		t := v.Type()
+		exported := false
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			exported = true
+			if f.Tag.Get("cache") == "-" {
+				continue
+			}
+			writeString(buf, f.Name)
+			if err := encodeKey(buf, v.Field(i)); err != nil {
+				return err
+			}
+		}
+		if !exported && t.NumField() > 0 {
+			return fmt.Errorf("request key: struct %s has no exported fields", t)
+		}
+	default:
+		return fmt.Errorf("request key: unsupported kind %s", v.Kind())
+	}
+	return nil
+}
+
+// encodeMarshaler writes v encoded by its encoding.BinaryMarshaler or encoding.TextMarshaler. It returns false if v
+// doesn't implement any of them.
+func encodeMarshaler(buf *bytes.Buffer, v reflect.Value) (bool, error) {
+	if v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer && v.IsNil() || !v.CanInterface() {
+		return false, nil
+	}
+	var (
+		data []byte
+		err  error
+	)
+	switch m := v.Interface().(type) {
+	case encoding.BinaryMarshaler:
+		data, err = m.MarshalBinary()
+	case encoding.TextMarshaler:
+		data, err = m.MarshalText()
+	default:
+		return false, nil
+	}
+	if err != nil {
+		return true, fmt.Errorf("request key: %w", err)
+	}
+	writeString(buf, string(data))
+	return true, nil
+}
+
+// writeUint writes u in fixed length.
+func writeUint(buf *bytes.Buffer, u uint64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], u)
+	buf.Write(b[:])
+}
+
+// writeString writes s with its length, so following values can't be mistaken for its part.
+func writeString(buf *bytes.Buffer, s string) {
+	writeUint(buf, uint64(len(s)))
+	buf.WriteString(s)
+}
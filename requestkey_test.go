@@ -0,0 +1,70 @@
+package gocollections
+
+import (
+	"testing"
+	"time"
+)
+
+type searchRequest struct {
+	Query   string
+	Filters map[string][]string
+	Page    *int
+	TraceID string `cache:"-"`
+	cursor  string
+}
+
+func TestRequestKey(t *testing.T) {
+	t.Parallel()
+
+	page1, page2 := 1, 1
+	req1 := searchRequest{Query: "go", Filters: map[string][]string{"a": {"1"}, "b": {"2"}}, Page: &page1,
+		TraceID: "trace1", cursor: "x"}
+	req2 := searchRequest{Query: "go", Filters: map[string][]string{"b": {"2"}, "a": {"1"}}, Page: &page2,
+		TraceID: "trace2", cursor: "y"}
+
+	key1, err := RequestKey(req1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key2, _ := RequestKey(req2)
+	if key1 != key2 {
+		t.Errorf("requests with the same fields should have the same key")
+	}
+
+	req2.Filters["a"] = []string{"1", "3"}
+	if key3, _ := RequestKey(req2); key3 == key1 {
+		t.Errorf("requests with different fields should have different keys")
+	}
+
+	// Concatenation of strings doesn't collide.
+	keyA, _ := RequestKey([]string{"ab", "c"})
+	keyB, _ := RequestKey([]string{"a", "bc"})
+	if keyA == keyB {
+		t.Errorf("different slices should have different keys")
+	}
+
+	if _, err := RequestKey(struct{ Fn func() }{}); err == nil {
+		t.Error("function should not be supported")
+	}
+}
+
+func TestRequestKey_Marshaler(t *testing.T) {
+	t.Parallel()
+
+	type window struct {
+		From time.Time
+	}
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	key1, err := RequestKey(window{From: from})
+	if err != nil {
+		t.Fatal(err)
+	}
+	key2, _ := RequestKey(window{From: from.Add(time.Hour)})
+	if key1 == key2 {
+		t.Errorf("different times should have different keys")
+	}
+
+	if _, err := RequestKey(struct{ cursor string }{cursor: "x"}); err == nil {
+		t.Error("struct without exported fields should not be supported")
+	}
+}
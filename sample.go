@@ -0,0 +1,74 @@
+package gocollections
+
+import "math/rand"
+
+/*
+Sampling
+*/
+
+// SampleKeys returns uniform random sample of at most n unexpired keys without iterating the whole map. It's useful
+// for diagnostics of huge maps.
+func (m *timeExpiredMap[K, V]) SampleKeys(n int) []K {
+	var result []K
+	for _, e := range m.SampleEntries(n) {
+		result = append(result, e.Key)
+	}
+	return result
+}
+
+// SampleEntries returns uniform random sample of at most n unexpired entries without iterating the whole map, ex. to
+// estimate value sizes or TTL distribution of huge map. Fewer entries may be returned if the map has many expired
+// elements which were not removed yet.
+func (m *timeExpiredMap[K, V]) SampleEntries(n int) []Entry[K, V] {
+	var result []Entry[K, V]
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed || n <= 0 {
+		return result
+	}
+	now := m.clock.Now()
+	add := func(i int) {
+		key := m.keys[i]
+		if e := m.data[key]; e.expiredAt.After(now) {
+			result = append(result, m.entry(key, e, now))
+		}
+	}
+
+	if 2*n >= len(m.keys) {
+		// Sample is a big part of the map, so the map is shuffled.
+		for _, i := range rand.Perm(len(m.keys)) {
+			if len(result) == n {
+				break
+			}
+			add(i)
+		}
+		return result
+	}
+
+	picked := make(map[int]struct{}, n)
+	for attempts := 0; len(result) < n && attempts < 4*n; attempts++ {
+		i := rand.Intn(len(m.keys))
+		if _, found := picked[i]; found {
+			continue
+		}
+		picked[i] = struct{}{}
+		add(i)
+	}
+	return result
+}
+
+// removeKey removes key at position pos from keys. The last key is moved to its position. It must be called with
+// locked mutex.
+func (m *timeExpiredMap[K, V]) removeKey(pos int) {
+	last := len(m.keys) - 1
+	if pos != last {
+		key := m.keys[last]
+		m.keys[pos] = key
+		e := m.data[key]
+		e.pos = pos
+		m.data[key] = e
+	}
+	var zero K
+	m.keys[last] = zero
+	m.keys = m.keys[:last]
+}
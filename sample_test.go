@@ -0,0 +1,65 @@
+package gocollections
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeExpiredMap_SampleEntries(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[int, int](time.Minute)
+	defer tmap.Discard()
+	for i := 0; i < 100; i++ {
+		tmap.Add(i, i*10)
+	}
+	if keys := tmap.SampleKeys(3); len(keys) != 3 {
+		t.Errorf("want: 3 keys, got: %v", keys)
+	}
+
+	for i := 0; i < 100; i += 2 {
+		if err := tmap.Del(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, n := range []int{5, 40, 100} {
+		entries := tmap.SampleEntries(n)
+		want := n
+		if want > 50 {
+			want = 50
+		}
+		if len(entries) > want || (n == 100 && len(entries) != want) {
+			t.Errorf("n %d: want: %d entries, got: %d", n, want, len(entries))
+		}
+		seen := make(map[int]bool)
+		for _, e := range entries {
+			if e.Key%2 == 0 || e.Value != e.Key*10 || seen[e.Key] {
+				t.Errorf("n %d: unexpected entry: %v", n, e)
+			}
+			seen[e.Key] = true
+		}
+	}
+}
+
+func TestTimeExpiredMap_SampleKeysAfterGroupEviction(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, int](time.Minute, Config{
+		GroupFn:     func(key any) string { return key.(string)[:1] },
+		GroupMaxLen: 1,
+	})
+	defer tmap.Discard()
+	tmap.Add("a1", 1)
+	tmap.Add("a2", 2)
+
+	if keys := tmap.SampleKeys(10); len(keys) != 1 || keys[0] != "a2" {
+		t.Errorf("want: [a2], got: %v", keys)
+	}
+	if err := tmap.Del("a2"); err != nil {
+		t.Fatal(err)
+	}
+	if keys := tmap.SampleKeys(10); len(keys) != 0 {
+		t.Errorf("want no keys, got: %v", keys)
+	}
+}
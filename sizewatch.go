@@ -0,0 +1,73 @@
+package gocollections
+
+/*
+Size watchdog
+*/
+
+// SizeWatch configures watchdog of the collection size, which gives early warning of key-cardinality explosions. Size
+// is checked by the cleaning goroutine after expired elements are removed, so growth and shrink are per
+// Config.CleanJobInterval. Limits which are 0 are not checked.
+type SizeWatch struct {
+	SoftLimit int                   // alert when size exceeds the limit
+	MaxGrowth int                   // alert when size grows more than MaxGrowth between checks
+	MaxShrink int                   // alert when size shrinks more than MaxShrink between checks
+	OnAlert   func(alert SizeAlert) // called with every alert
+}
+
+// SizeAlertKind is kind of SizeAlert.
+type SizeAlertKind int
+
+const (
+	SizeOverLimit SizeAlertKind = iota // Size exceeded SizeWatch.SoftLimit.
+	SizeGrowth                         // Size grew more than SizeWatch.MaxGrowth.
+	SizeShrink                         // Size shrank more than SizeWatch.MaxShrink.
+)
+
+// SizeAlert is an alert of SizeWatch.
+type SizeAlert struct {
+	Name     string // name of the collection
+	Kind     SizeAlertKind
+	Size     int // current size
+	Previous int // size at the previous check
+}
+
+// sizeWatcher keeps size of the previous check.
+type sizeWatcher struct {
+	last    int
+	checked bool
+}
+
+// check returns alerts of the size.
+func (w *sizeWatcher) check(config Config, size int) []SizeAlert {
+	sw := config.SizeWatch
+	if sw.OnAlert == nil {
+		return nil
+	}
+	last, checked := w.last, w.checked
+	w.last, w.checked = size, true
+
+	var alerts []SizeAlert
+	alert := func(kind SizeAlertKind) {
+		alerts = append(alerts, SizeAlert{Name: config.Name, Kind: kind, Size: size, Previous: last})
+	}
+	if sw.SoftLimit > 0 && size > sw.SoftLimit && (!checked || last <= sw.SoftLimit) {
+		alert(SizeOverLimit)
+	}
+	if !checked {
+		return alerts
+	}
+	if sw.MaxGrowth > 0 && size-last > sw.MaxGrowth {
+		alert(SizeGrowth)
+	}
+	if sw.MaxShrink > 0 && last-size > sw.MaxShrink {
+		alert(SizeShrink)
+	}
+	return alerts
+}
+
+// notify calls SizeWatch.OnAlert with alerts.
+func (sw SizeWatch) notify(alerts []SizeAlert) {
+	for _, a := range alerts {
+		sw.OnAlert(a)
+	}
+}
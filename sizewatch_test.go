@@ -0,0 +1,55 @@
+package gocollections
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSizeWatcher_Check(t *testing.T) {
+	t.Parallel()
+
+	var alerts []SizeAlert
+	config := Config{Name: "test", SizeWatch: SizeWatch{SoftLimit: 5, MaxGrowth: 3, MaxShrink: 2,
+		OnAlert: func(alert SizeAlert) { alerts = append(alerts, alert) }}}
+	var w sizeWatcher
+
+	for _, size := range []int{1, 3, 7, 8, 4, 3} {
+		config.SizeWatch.notify(w.check(config, size))
+	}
+
+	want := []SizeAlert{
+		{Name: "test", Kind: SizeOverLimit, Size: 7, Previous: 3},
+		{Name: "test", Kind: SizeGrowth, Size: 7, Previous: 3},
+		{Name: "test", Kind: SizeShrink, Size: 4, Previous: 8},
+	}
+	if len(alerts) != len(want) {
+		t.Fatalf("want: %v, got: %v", want, alerts)
+	}
+	for i := range want {
+		if alerts[i] != want[i] {
+			t.Errorf("alert %d want: %v, got: %v", i, want[i], alerts[i])
+		}
+	}
+}
+
+func TestTimeExpiredMap_SizeWatch(t *testing.T) {
+	t.Parallel()
+
+	alertChan := make(chan SizeAlert, 10)
+	tmap := NewTimeExpiredMap[int, int](time.Minute, Config{CleanJobInterval: 10 * time.Millisecond,
+		SizeWatch: SizeWatch{SoftLimit: 2, OnAlert: func(alert SizeAlert) { alertChan <- alert }}})
+	defer tmap.Discard()
+
+	for i := 0; i < 3; i++ {
+		tmap.Add(i, i)
+	}
+
+	select {
+	case alert := <-alertChan:
+		if alert.Kind != SizeOverLimit || alert.Size != 3 {
+			t.Errorf("unexpected alert: %v", alert)
+		}
+	case <-time.After(time.Second):
+		t.Error("alert not received")
+	}
+}
@@ -0,0 +1,176 @@
+package gocollections
+
+/*
+Slice utilities
+*/
+
+// Pair holds two values, ex. elements returned by Zip.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Zip returns pairs of elements with the same index from both slices. Length of the result is length of the shorter
+// slice.
+func Zip[A, B any](a []A, b []B) []Pair[A, B] {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	result := make([]Pair[A, B], n)
+	for i := 0; i < n; i++ {
+		result[i] = Pair[A, B]{First: a[i], Second: b[i]}
+	}
+	return result
+}
+
+// Partition splits values to values which satisfy pred and values which don't. Order of values is kept.
+func Partition[V any](values []V, pred func(V) bool) (matched, rest []V) {
+	for _, v := range values {
+		if pred(v) {
+			matched = append(matched, v)
+		} else {
+			rest = append(rest, v)
+		}
+	}
+	return matched, rest
+}
+
+// SlidingWindow returns all windows of n consecutive values. Windows share memory with values. It returns nil if n is
+// not positive or bigger than length of values.
+func SlidingWindow[V any](values []V, n int) [][]V {
+	if n <= 0 || n > len(values) {
+		return nil
+	}
+	result := make([][]V, 0, len(values)-n+1)
+	for i := 0; i+n <= len(values); i++ {
+		result = append(result, values[i:i+n:i+n])
+	}
+	return result
+}
+
+// Batch splits values to batches of size n. The last batch can be smaller. Batches share memory with values. It
+// returns nil if n is not positive.
+func Batch[V any](values []V, n int) [][]V {
+	if n <= 0 {
+		return nil
+	}
+	result := make([][]V, 0, (len(values)+n-1)/n)
+	for i := 0; i < len(values); i += n {
+		end := i + n
+		if end > len(values) {
+			end = len(values)
+		}
+		result = append(result, values[i:end:end])
+	}
+	return result
+}
+
+// Seq is an iterator which calls yield for every value until yield returns false. It has the same shape as Range
+// methods of collections, ex. Seq[V](list.Range).
+type Seq[V any] func(yield func(V) bool)
+
+// ZipSeq returns iterator of pairs of values with the same position in both iterators. It ends with the shorter
+// iterator. Iterator b runs in its own goroutine, which ends when the returned iterator ends.
+func ZipSeq[A, B any](a Seq[A], b Seq[B]) Seq[Pair[A, B]] {
+	return func(yield func(Pair[A, B]) bool) {
+		next, stop := pull(b)
+		defer stop()
+		a(func(va A) bool {
+			vb, ok := next()
+			if !ok {
+				return false
+			}
+			return yield(Pair[A, B]{First: va, Second: vb})
+		})
+	}
+}
+
+// pull converts iterator to function next returning its values one by one. Function stop ends the iterator and must be
+// called when values are not needed any more.
+func pull[V any](seq Seq[V]) (next func() (V, bool), stop func()) {
+	values := make(chan V)
+	done := make(chan struct{})
+	go func() {
+		defer close(values)
+		seq(func(v V) bool {
+			select {
+			case values <- v:
+				return true
+			case <-done:
+				return false
+			}
+		})
+	}()
+	next = func() (V, bool) {
+		v, ok := <-values
+		return v, ok
+	}
+	stop = func() {
+		close(done)
+		for range values {
+		}
+	}
+	return next, stop
+}
+
+// PartitionSeq splits values of the iterator to values which satisfy pred and values which don't. Order of values is
+// kept.
+func PartitionSeq[V any](seq Seq[V], pred func(V) bool) (matched, rest []V) {
+	seq(func(v V) bool {
+		if pred(v) {
+			matched = append(matched, v)
+		} else {
+			rest = append(rest, v)
+		}
+		return true
+	})
+	return matched, rest
+}
+
+// SlidingWindowSeq returns iterator of all windows of n consecutive values of the iterator. Every window is a new
+// slice. It returns empty iterator if n is not positive.
+func SlidingWindowSeq[V any](seq Seq[V], n int) Seq[[]V] {
+	return func(yield func([]V) bool) {
+		if n <= 0 {
+			return
+		}
+		window := make([]V, 0, n)
+		seq(func(v V) bool {
+			if len(window) == n {
+				copy(window, window[1:])
+				window = window[:n-1]
+			}
+			window = append(window, v)
+			if len(window) < n {
+				return true
+			}
+			return yield(append([]V(nil), window...))
+		})
+	}
+}
+
+// BatchSeq returns iterator of batches of size n of values of the iterator. The last batch can be smaller. It returns
+// empty iterator if n is not positive.
+func BatchSeq[V any](seq Seq[V], n int) Seq[[]V] {
+	return func(yield func([]V) bool) {
+		if n <= 0 {
+			return
+		}
+		var batch []V
+		stopped := false
+		seq(func(v V) bool {
+			batch = append(batch, v)
+			if len(batch) < n {
+				return true
+			}
+			full := batch
+			batch = nil
+			stopped = !yield(full)
+			return !stopped
+		})
+		if !stopped && len(batch) > 0 {
+			yield(batch)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package gocollections
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSliceUtilities(t *testing.T) {
+	t.Parallel()
+
+	values := []int{1, 2, 3, 4, 5}
+
+	zipped := Zip(values, []string{"a", "b"})
+	if want := []Pair[int, string]{{1, "a"}, {2, "b"}}; !reflect.DeepEqual(zipped, want) {
+		t.Errorf("want: %v, got: %v", want, zipped)
+	}
+
+	even, odd := Partition(values, func(v int) bool { return v%2 == 0 })
+	if !reflect.DeepEqual(even, []int{2, 4}) || !reflect.DeepEqual(odd, []int{1, 3, 5}) {
+		t.Errorf("Expect [2 4] and [1 3 5], but got %v and %v", even, odd)
+	}
+
+	windows := SlidingWindow(values, 3)
+	if want := [][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}; !reflect.DeepEqual(windows, want) {
+		t.Errorf("want: %v, got: %v", want, windows)
+	}
+
+	batches := Batch(values, 2)
+	if want := [][]int{{1, 2}, {3, 4}, {5}}; !reflect.DeepEqual(batches, want) {
+		t.Errorf("want: %v, got: %v", want, batches)
+	}
+}
+
+// seqOf returns iterator of values.
+func seqOf[V any](values ...V) Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range values {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// collect returns values of the iterator.
+func collect[V any](seq Seq[V]) []V {
+	var result []V
+	seq(func(v V) bool {
+		result = append(result, v)
+		return true
+	})
+	return result
+}
+
+func TestSeqUtilities(t *testing.T) {
+	t.Parallel()
+
+	values := seqOf(1, 2, 3, 4, 5)
+
+	zipped := collect(ZipSeq(values, seqOf("a", "b")))
+	if want := []Pair[int, string]{{1, "a"}, {2, "b"}}; !reflect.DeepEqual(zipped, want) {
+		t.Errorf("want: %v, got: %v", want, zipped)
+	}
+
+	even, odd := PartitionSeq(values, func(v int) bool { return v%2 == 0 })
+	if !reflect.DeepEqual(even, []int{2, 4}) || !reflect.DeepEqual(odd, []int{1, 3, 5}) {
+		t.Errorf("Expect [2 4] and [1 3 5], but got %v and %v", even, odd)
+	}
+
+	windows := collect(SlidingWindowSeq(values, 3))
+	if want := [][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}; !reflect.DeepEqual(windows, want) {
+		t.Errorf("want: %v, got: %v", want, windows)
+	}
+
+	batches := collect(BatchSeq(values, 2))
+	if want := [][]int{{1, 2}, {3, 4}, {5}}; !reflect.DeepEqual(batches, want) {
+		t.Errorf("want: %v, got: %v", want, batches)
+	}
+
+	// Iterators stop when yield returns false.
+	first := 0
+	BatchSeq(values, 2)(func(batch []int) bool {
+		first++
+		return false
+	})
+	if first != 1 {
+		t.Errorf("Expect iteration stopped after first batch, but got %d batches", first)
+	}
+
+	tlist := NewTimeExpiredList[int](time.Minute)
+	defer tlist.Discard()
+	tlist.Add(1)
+	tlist.Add(2)
+	if got := collect(Seq[int](tlist.Range)); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("want: [1 2], got: %v", got)
+	}
+}
@@ -0,0 +1,133 @@
+package gocollections
+
+import (
+	"sort"
+	"sync"
+)
+
+/*
+Sorted List
+*/
+
+// SortedList is a list which keeps elements sorted by comparator. Lookups use binary search.
+type SortedList[T any] interface {
+	Insert(value T)
+	Get(index int) (T, error)
+	GetAll() []T
+	Del(index int) error
+	Search(value T) (int, bool)
+	RankOf(value T) int
+	Range(from, to int) ([]T, error)
+	Size() int
+	Clear()
+}
+
+type sortedList[T any] struct {
+	mu   sync.Mutex
+	cmp  func(a, b T) int
+	data []T
+}
+
+// NewSortedList creates new SortedList. Comparator cmp returns negative number if a is less than b, 0 if they are
+// equal and positive number if a is greater than b.
+func NewSortedList[T any](cmp func(a, b T) int) SortedList[T] {
+	return &sortedList[T]{
+		cmp: cmp,
+	}
+}
+
+// Insert adds element to the list on the position given by comparator. Equal elements keep insertion order.
+func (l *sortedList[T]) Insert(value T) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	i := sort.Search(len(l.data), func(i int) bool {
+		return l.cmp(l.data[i], value) > 0
+	})
+	var zero T
+	l.data = append(l.data, zero)
+	copy(l.data[i+1:], l.data[i:])
+	l.data[i] = value
+}
+
+// Get returns element by index.
+func (l *sortedList[T]) Get(index int) (T, error) {
+	var result T
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if index < 0 || index >= len(l.data) {
+		return result, &IndexError{Index: index, Len: len(l.data)}
+	}
+	return l.data[index], nil
+}
+
+// GetAll returns all elements in sorted order.
+func (l *sortedList[T]) GetAll() []T {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	result := make([]T, len(l.data))
+	copy(result, l.data)
+	return result
+}
+
+// Del removes element by index.
+func (l *sortedList[T]) Del(index int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if index < 0 || index >= len(l.data) {
+		return &IndexError{Index: index, Len: len(l.data)}
+	}
+	l.data = append(l.data[:index], l.data[index+1:]...)
+	return nil
+}
+
+// Search returns index of the first element equal to value. If there is no such element, it returns index where the
+// value would be inserted and false.
+func (l *sortedList[T]) Search(value T) (int, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	i := l.rankOf(value)
+	return i, i < len(l.data) && l.cmp(l.data[i], value) == 0
+}
+
+// RankOf returns number of elements less than value.
+func (l *sortedList[T]) RankOf(value T) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rankOf(value)
+}
+
+// rankOf returns number of elements less than value. It must be called with locked mutex.
+func (l *sortedList[T]) rankOf(value T) int {
+	return sort.Search(len(l.data), func(i int) bool {
+		return l.cmp(l.data[i], value) >= 0
+	})
+}
+
+// Range returns copy of elements with index from from (inclusive) to to (exclusive).
+func (l *sortedList[T]) Range(from, to int) ([]T, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if from < 0 || from > len(l.data) {
+		return nil, &IndexError{Index: from, Len: len(l.data)}
+	}
+	if to < from || to > len(l.data) {
+		return nil, &IndexError{Index: to, Len: len(l.data)}
+	}
+	result := make([]T, to-from)
+	copy(result, l.data[from:to])
+	return result, nil
+}
+
+// Size returns size of the list.
+func (l *sortedList[T]) Size() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.data)
+}
+
+// Clear method clears all elements from the list.
+func (l *sortedList[T]) Clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.data = nil
+}
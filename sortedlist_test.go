@@ -0,0 +1,41 @@
+package gocollections
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSortedList(t *testing.T) {
+	t.Parallel()
+
+	slist := NewSortedList(func(a, b int) int { return a - b })
+
+	for _, v := range []int{5, 1, 4, 2, 4} {
+		slist.Insert(v)
+	}
+	if want := []int{1, 2, 4, 4, 5}; !reflect.DeepEqual(slist.GetAll(), want) {
+		t.Errorf("want: %v, got: %v", want, slist.GetAll())
+	}
+
+	if i, found := slist.Search(4); !found || i != 2 {
+		t.Errorf("Expect 4 found at index 2, but got %d, %t", i, found)
+	}
+	if i, found := slist.Search(3); found || i != 2 {
+		t.Errorf("Expect 3 not found with insert index 2, but got %d, %t", i, found)
+	}
+	if rank := slist.RankOf(5); rank != 4 {
+		t.Errorf("want: %d, got: %d", 4, rank)
+	}
+
+	values, err := slist.Range(1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{2, 4}; !reflect.DeepEqual(values, want) {
+		t.Errorf("want: %v, got: %v", want, values)
+	}
+	if _, err = slist.Range(2, 10); !errors.Is(err, ErrIndexOutOfBound) {
+		t.Errorf("Expect ErrIndexOutOfBound but got %v", err)
+	}
+}
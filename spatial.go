@@ -0,0 +1,256 @@
+package gocollections
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+/*
+Spatial Map
+*/
+
+// Point is a position in 2D space.
+type Point struct {
+	X, Y float64
+}
+
+// PointEntry is a point with its id returned from SpatialMap queries.
+type PointEntry[K comparable] struct {
+	ID    K
+	Point Point
+}
+
+// SpatialMap is a map of points with id which expire base on expiration duration. Points are bucketed in square cells,
+// so queries check only the cells in the queried area. Implementation of this map is running goroutine which removes
+// expired points. To stop this goroutine call Discard() method when this map is not needed any more.
+type SpatialMap[K comparable] interface {
+	InsertPoint(id K, p Point)
+	InsertPointWithDuration(id K, p Point, duration time.Duration)
+	Get(id K) (Point, error)
+	Del(id K) error
+	QueryRect(min, max Point) []PointEntry[K]
+	QueryRadius(center Point, radius float64) []PointEntry[K]
+	Size() int
+	Discard()
+}
+
+type cell struct {
+	x, y int64
+}
+
+type spatialElement struct {
+	point     Point
+	cell      cell
+	expiredAt time.Time
+}
+
+type spatialMap[K comparable] struct {
+	config   Config
+	mu       sync.Mutex
+	duration time.Duration // default point duration
+	cellSize float64
+	points   map[K]spatialElement
+	cells    map[cell]map[K]struct{} // ids of points in the cell
+	quitChan chan struct{}
+	clock    *coarseClock    // cached clock if Config.TimeResolution is set
+	suspend  suspendDetector // detects suspend of the process between cleaning runs
+	closed   bool            // true after Discard
+}
+
+// NewSpatialMap creates new SpatialMap with square cells of cellSize. Cell size should be about the size of common
+// query area. It runs goroutine for removing expired points. It panics if cellSize is not positive.
+func NewSpatialMap[K comparable](cellSize float64, duration time.Duration, configs ...Config) SpatialMap[K] {
+	if !(cellSize > 0) {
+		panic("gocollections: spatial map cell size must be positive")
+	}
+	config := newConfig(configs)
+	smap := &spatialMap[K]{
+		config:   config,
+		duration: duration,
+		cellSize: cellSize,
+		points:   make(map[K]spatialElement),
+		cells:    make(map[cell]map[K]struct{}),
+		quitChan: make(chan struct{}),
+		clock:    newCoarseClock(config),
+	}
+
+	startCleaner(config, smap.quitChan, smap.removeExpired)
+
+	return smap
+}
+
+// InsertPoint adds point with id to the map. If id is already in the map, the point is moved.
+func (m *spatialMap[K]) InsertPoint(id K, p Point) {
+	m.InsertPointWithDuration(id, p, m.duration)
+}
+
+// InsertPointWithDuration adds point with id and custom duration to the map. If id is already in the map, the point is
+// moved.
+func (m *spatialMap[K]) InsertPointWithDuration(id K, p Point, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return
+	}
+	if e, found := m.points[id]; found {
+		m.removeFromCell(id, e.cell)
+	}
+	c := m.cellOf(p)
+	m.points[id] = spatialElement{point: p, cell: c, expiredAt: m.clock.expiry(duration)}
+	ids, found := m.cells[c]
+	if !found {
+		ids = make(map[K]struct{})
+		m.cells[c] = ids
+	}
+	ids[id] = struct{}{}
+}
+
+// Get returns point by id.
+func (m *spatialMap[K]) Get(id K) (Point, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return Point{}, ErrClosed
+	}
+	e, found := m.points[id]
+	if !found {
+		return Point{}, &KeyError{Key: id}
+	}
+	if e.expiredAt.Before(m.clock.Now()) {
+		return Point{}, &ExpiredError{Key: id, ExpiredAt: e.expiredAt}
+	}
+	return e.point, nil
+}
+
+// Del removes point by id.
+func (m *spatialMap[K]) Del(id K) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return ErrClosed
+	}
+	e, found := m.points[id]
+	if !found {
+		return &KeyError{Key: id}
+	}
+	m.delete(id, e)
+	return nil
+}
+
+// QueryRect returns unexpired points inside rectangle given by min and max corner.
+func (m *spatialMap[K]) QueryRect(min, max Point) []PointEntry[K] {
+	return m.query(min, max, func(p Point) bool {
+		return p.X >= min.X && p.X <= max.X && p.Y >= min.Y && p.Y <= max.Y
+	})
+}
+
+// QueryRadius returns unexpired points in distance up to radius from center.
+func (m *spatialMap[K]) QueryRadius(center Point, radius float64) []PointEntry[K] {
+	min := Point{X: center.X - radius, Y: center.Y - radius}
+	max := Point{X: center.X + radius, Y: center.Y + radius}
+	return m.query(min, max, func(p Point) bool {
+		dx, dy := p.X-center.X, p.Y-center.Y
+		return dx*dx+dy*dy <= radius*radius
+	})
+}
+
+// query returns unexpired points from cells covering rectangle min, max which match the filter.
+func (m *spatialMap[K]) query(min, max Point, match func(p Point) bool) []PointEntry[K] {
+	var result []PointEntry[K]
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.clock.Now()
+	add := func(id K, e spatialElement) {
+		if e.expiredAt.After(now) && match(e.point) {
+			result = append(result, PointEntry[K]{ID: id, Point: e.point})
+		}
+	}
+
+	minCell, maxCell := m.cellOf(min), m.cellOf(max)
+	cellCount := float64(maxCell.x-minCell.x+1) * float64(maxCell.y-minCell.y+1)
+	if cellCount > float64(len(m.cells)) {
+		// Area covers more cells than are used, so it's faster to check all points.
+		for id, e := range m.points {
+			add(id, e)
+		}
+		return result
+	}
+	for x := minCell.x; x <= maxCell.x; x++ {
+		for y := minCell.y; y <= maxCell.y; y++ {
+			for id := range m.cells[cell{x: x, y: y}] {
+				add(id, m.points[id])
+			}
+		}
+	}
+	return result
+}
+
+// Size returns number of unexpired points.
+func (m *spatialMap[K]) Size() int {
+	var count = 0
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.clock.Now()
+	for _, e := range m.points {
+		if e.expiredAt.After(now) {
+			count++
+		}
+	}
+	return count
+}
+
+// Discard method stops the goroutine for removing points and discards data in internal maps.
+func (m *spatialMap[K]) Discard() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return
+	}
+	m.closed = true
+	close(m.quitChan)
+	m.clock.stop()
+	m.points = nil
+	m.cells = nil
+}
+
+// cellOf returns cell of the point.
+func (m *spatialMap[K]) cellOf(p Point) cell {
+	return cell{x: int64(math.Floor(p.X / m.cellSize)), y: int64(math.Floor(p.Y / m.cellSize))}
+}
+
+// delete removes point from the map. It must be called with locked mutex.
+func (m *spatialMap[K]) delete(id K, e spatialElement) {
+	m.removeFromCell(id, e.cell)
+	delete(m.points, id)
+}
+
+// removeFromCell removes id from the cell and the cell if it's empty. It must be called with locked mutex.
+func (m *spatialMap[K]) removeFromCell(id K, c cell) {
+	ids := m.cells[c]
+	delete(ids, id)
+	if len(ids) == 0 {
+		delete(m.cells, c)
+	}
+}
+
+// removeExpired method removes expired points.
+func (m *spatialMap[K]) removeExpired() {
+	m.suspend.handle(m.config, &m.mu, m.shiftExpirations)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.clock.Now()
+	for id, e := range m.points {
+		if e.expiredAt.Before(now) {
+			m.delete(id, e)
+		}
+	}
+}
+
+// shiftExpirations moves expirations of all points by d. It must be called with locked mutex.
+func (m *spatialMap[K]) shiftExpirations(d time.Duration) {
+	for id, e := range m.points {
+		e.expiredAt = shiftExpiry(e.expiredAt, d)
+		m.points[id] = e
+	}
+}
@@ -0,0 +1,72 @@
+package gocollections
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSpatialMap(t *testing.T) {
+	t.Parallel()
+
+	smap := NewSpatialMap[string](10, 10*time.Second)
+	defer smap.Discard()
+
+	smap.InsertPoint("car1", Point{X: 1, Y: 1})
+	smap.InsertPoint("car2", Point{X: 5, Y: 5})
+	smap.InsertPoint("car3", Point{X: 50, Y: 50})
+	smap.InsertPointWithDuration("car4", Point{X: 2, Y: 2}, -1*time.Second)
+
+	if got := smap.QueryRadius(Point{X: 0, Y: 0}, 3); len(got) != 1 || got[0].ID != "car1" {
+		t.Errorf("Expect car1 in radius, but got %v", got)
+	}
+	if got := smap.QueryRect(Point{X: 0, Y: 0}, Point{X: 20, Y: 20}); len(got) != 2 {
+		t.Errorf("Expect 2 points in rectangle, but got %v", got)
+	}
+
+	// Move car3 close to the others.
+	smap.InsertPoint("car3", Point{X: 3, Y: 3})
+	if got := smap.QueryRect(Point{X: 0, Y: 0}, Point{X: 20, Y: 20}); len(got) != 3 {
+		t.Errorf("Expect 3 points in rectangle, but got %v", got)
+	}
+	if got := smap.QueryRect(Point{X: -1000, Y: -1000}, Point{X: 1000, Y: 1000}); len(got) != 3 {
+		t.Errorf("Expect 3 points in large rectangle, but got %v", got)
+	}
+	if smap.Size() != 3 {
+		t.Errorf("want: %d, got: %d", 3, smap.Size())
+	}
+}
+
+func TestNewSpatialMap_InvalidCellSize(t *testing.T) {
+	t.Parallel()
+
+	for _, cellSize := range []float64{0, -1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expect panic for cell size %v", cellSize)
+				}
+			}()
+			NewSpatialMap[string](cellSize, time.Second)
+		}()
+	}
+}
+
+func TestSpatialMap_Suspend(t *testing.T) {
+	t.Parallel()
+
+	smap := NewSpatialMap[string](10, 10*time.Second, Config{SuspendPolicy: SuspendExpire})
+	defer smap.Discard()
+	smap.InsertPoint("expiring", Point{X: 1, Y: 1})
+	smap.InsertPointWithDuration("persistent", Point{X: 2, Y: 2}, NoExpiry)
+
+	// Simulate expiring of points lapsed during a minute of suspend.
+	m := smap.(*spatialMap[string])
+	m.mu.Lock()
+	m.shiftExpirations(m.config.suspendShift(time.Minute))
+	m.mu.Unlock()
+	m.removeExpired()
+
+	if size := smap.Size(); size != 1 {
+		t.Errorf("Expect only point without expiration kept, but got size %d", size)
+	}
+}
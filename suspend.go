@@ -0,0 +1,85 @@
+package gocollections
+
+import (
+	"sync"
+	"time"
+)
+
+// SuspendPolicy defines how collections handle suspend of the process, ex. laptop sleep or VM pause. Suspend is
+// detected by the cleaning goroutine as difference between wall clock and monotonic clock.
+type SuspendPolicy int
+
+const (
+	SuspendIgnore SuspendPolicy = iota // Suspend is not detected.
+	SuspendExpire                      // Elements which lapsed during suspend expire.
+	SuspendExtend                      // Elements are extended by duration of the suspend.
+)
+
+// defaultSuspendThreshold is minimal detected suspend if Config.SuspendThreshold is not set.
+const defaultSuspendThreshold = 1 * time.Second
+
+// suspendDetector detects suspend of the process between cleaning runs.
+type suspendDetector struct {
+	last time.Time
+}
+
+// check returns duration of suspend since the last check, or 0 if no suspend is detected.
+func (d *suspendDetector) check(config Config) time.Duration {
+	now := time.Now()
+	last := d.last
+	d.last = now
+	if config.SuspendPolicy == SuspendIgnore || last.IsZero() {
+		return 0
+	}
+	// Monotonic clock doesn't run during suspend, wall clock does.
+	gap := now.Round(0).Sub(last.Round(0)) - now.Sub(last)
+	threshold := config.SuspendThreshold
+	if threshold <= 0 {
+		threshold = defaultSuspendThreshold
+	}
+	if gap < threshold {
+		return 0
+	}
+	return gap
+}
+
+// handle detects suspend and moves expirations of the collection by Config.SuspendPolicy. Function shift moves
+// expirations of all elements of the collection, it's called with locked mu only if they have to be moved.
+// Config.OnSuspend is called after mu is unlocked.
+func (d *suspendDetector) handle(config Config, mu sync.Locker, shift func(d time.Duration)) {
+	mu.Lock()
+	gap := d.check(config)
+	if s := config.suspendShift(gap); s != 0 {
+		shift(s)
+	}
+	mu.Unlock()
+	config.notifySuspend(gap)
+}
+
+// shiftExpiry returns expiredAt moved by d. Expiration of elements which never expire is not moved.
+func shiftExpiry(expiredAt time.Time, d time.Duration) time.Time {
+	if expiredAt.Equal(noExpiryTime) {
+		return expiredAt
+	}
+	return expiredAt.Add(d)
+}
+
+// suspendShift returns how expirations have to be moved after suspend. Monotonic expirations are extended by the
+// suspend already and wall clock expirations lapse during suspend.
+func (c Config) suspendShift(gap time.Duration) time.Duration {
+	switch {
+	case c.SuspendPolicy == SuspendExpire && !c.WallClock:
+		return -gap
+	case c.SuspendPolicy == SuspendExtend && c.WallClock:
+		return gap
+	default:
+		return 0
+	}
+}
+
+// notifySuspend calls Config.OnSuspend if suspend was detected.
+func (c Config) notifySuspend(gap time.Duration) {
+	if gap > 0 && c.OnSuspend != nil {
+		c.OnSuspend(gap, c.SuspendPolicy)
+	}
+}
@@ -0,0 +1,46 @@
+package gocollections
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSuspendShift(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		config Config
+		want   time.Duration
+	}{
+		{Config{SuspendPolicy: SuspendIgnore}, 0},
+		{Config{SuspendPolicy: SuspendExpire}, -time.Minute},
+		{Config{SuspendPolicy: SuspendExtend}, 0},
+		{Config{SuspendPolicy: SuspendExpire, WallClock: true}, 0},
+		{Config{SuspendPolicy: SuspendExtend, WallClock: true}, time.Minute},
+	}
+	for _, tt := range tests {
+		if got := tt.config.suspendShift(time.Minute); got != tt.want {
+			t.Errorf("policy %d, wall clock %v: want: %v, got: %v", tt.config.SuspendPolicy, tt.config.WallClock,
+				tt.want, got)
+		}
+	}
+}
+
+func TestTimeExpiredMap_Suspend(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](10*time.Second, Config{SuspendPolicy: SuspendExpire})
+	defer tmap.Discard()
+	tmap.Add("key1", "value1")
+
+	// Simulate expiring of elements lapsed during a minute of suspend.
+	m := tmap.(*timeExpiredMap[string, string])
+	m.mu.Lock()
+	m.shiftExpirations(m.config.suspendShift(time.Minute))
+	m.mu.Unlock()
+	m.removeExpired()
+
+	if tmap.SizeRaw() != 0 {
+		t.Error("Expect element lapsed during suspend removed")
+	}
+}
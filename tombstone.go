@@ -0,0 +1,70 @@
+package gocollections
+
+import "time"
+
+/*
+Tombstones
+*/
+
+// SoftDel removes unexpired element from the map like Del and keeps its tombstone for Config.TombstoneTTL, so late
+// writers can detect recently deleted key by IsDeleted or AddUnlessDeleted, ex. when the map fronts eventually
+// consistent store. It returns KeyError if the key is not in the map.
+func (m *timeExpiredMap[K, V]) SoftDel(key K) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return ErrClosed
+	}
+	e, found := m.data[key]
+	if !found || e.expiredAt.Before(m.clock.Now()) {
+		return &KeyError{Key: key}
+	}
+	m.remove(key)
+	ttl := m.config.TombstoneTTL
+	if ttl <= 0 {
+		ttl = m.duration
+	}
+	if m.tombstones == nil {
+		m.tombstones = make(map[K]time.Time)
+	}
+	m.tombstones[key] = m.clock.expiry(ttl)
+	return nil
+}
+
+// IsDeleted returns true if the key was deleted by SoftDel and its tombstone didn't expire.
+func (m *timeExpiredMap[K, V]) IsDeleted(key K) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.isDeleted(key, m.clock.Now())
+}
+
+// AddUnlessDeleted adds element to the map with default duration unless the key has tombstone. It returns ErrDeleted
+// if the key was deleted by SoftDel, ErrRejected if the element was screened and ErrClosed after Discard. Add of the
+// key removes its tombstone.
+func (m *timeExpiredMap[K, V]) AddUnlessDeleted(key K, data V) error {
+	duration, err := m.prepareAdd(key, data, m.duration)
+	if err != nil {
+		return err
+	}
+	defer m.mu.Unlock()
+	if m.isDeleted(key, m.clock.Now()) {
+		return ErrDeleted
+	}
+	m.set(key, data, duration)
+	return nil
+}
+
+// isDeleted returns true if the key has unexpired tombstone. It must be called with locked mutex.
+func (m *timeExpiredMap[K, V]) isDeleted(key K, now time.Time) bool {
+	expiredAt, found := m.tombstones[key]
+	return found && expiredAt.After(now)
+}
+
+// removeExpiredTombstones removes expired tombstones. It must be called with locked mutex.
+func (m *timeExpiredMap[K, V]) removeExpiredTombstones(now time.Time) {
+	for key, expiredAt := range m.tombstones {
+		if !expiredAt.After(now) {
+			delete(m.tombstones, key)
+		}
+	}
+}
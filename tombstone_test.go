@@ -0,0 +1,44 @@
+package gocollections
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTimeExpiredMap_SoftDel(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](time.Minute, Config{TombstoneTTL: 30 * time.Millisecond,
+		CleanJobInterval: 10 * time.Millisecond})
+	defer tmap.Discard()
+	tmap.Add("key1", "value1")
+
+	if err := tmap.SoftDel("key1"); err != nil {
+		t.Fatal(err)
+	}
+	if !tmap.IsDeleted("key1") || tmap.Contains("key1") {
+		t.Error("key should be deleted with tombstone")
+	}
+	if err := tmap.AddUnlessDeleted("key1", "late"); !errors.Is(err, ErrDeleted) {
+		t.Errorf("want: %v, got: %v", ErrDeleted, err)
+	}
+	if err := tmap.SoftDel("key1"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("want: %v, got: %v", ErrKeyNotFound, err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if tmap.IsDeleted("key1") {
+		t.Error("tombstone should expire")
+	}
+	if err := tmap.AddUnlessDeleted("key1", "value2"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// Add resurrects deleted key.
+	_ = tmap.SoftDel("key1")
+	tmap.Add("key1", "value3")
+	if tmap.IsDeleted("key1") {
+		t.Error("Add should remove tombstone")
+	}
+}
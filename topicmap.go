@@ -0,0 +1,194 @@
+package gocollections
+
+import (
+	"sync"
+	"time"
+)
+
+/*
+Topic Map
+*/
+
+// TopicMap is a publish/subscribe map of topics. Published messages are sent to channels of all subscribers of the
+// topic. Idle subscriptions expire after TTL and their channels are closed. Subscription is idle if it receives no
+// message and is not touched, or if its channel is full. Implementation of this map is running goroutine which removes
+// expired subscriptions. To stop this goroutine call Discard() method when this map is not needed any more.
+type TopicMap[T any] interface {
+	Subscribe(topic string) Subscription[T]
+	Publish(topic string, msg T) int
+	Topics() []string
+	Discard()
+}
+
+// Subscription is a subscription to the topic of TopicMap.
+type Subscription[T any] interface {
+	// C returns channel with published messages. The channel is closed when the subscription expires or is
+	// unsubscribed.
+	C() <-chan T
+	// Touch extends the subscription TTL.
+	Touch()
+	// Unsubscribe removes the subscription and closes its channel.
+	Unsubscribe()
+}
+
+type subscription[T any] struct {
+	tm        *topicMap[T]
+	id        uint64
+	topic     string
+	ch        chan T
+	expiredAt time.Time
+}
+
+type topicMap[T any] struct {
+	config     Config
+	mu         sync.Mutex
+	ttl        time.Duration
+	bufferSize int
+	lastID     uint64
+	topics     map[string]map[uint64]*subscription[T]
+	quitChan   chan struct{}
+	clock      *coarseClock    // cached clock if Config.TimeResolution is set
+	suspend    suspendDetector // detects suspend of the process between cleaning runs
+	closed     bool            // true after Discard
+}
+
+// NewTopicMap creates new TopicMap. Subscriptions expire after idle ttl and their channels have bufferSize. It runs
+// goroutine for removing expired subscriptions.
+func NewTopicMap[T any](ttl time.Duration, bufferSize int, configs ...Config) TopicMap[T] {
+	config := newConfig(configs)
+	tm := &topicMap[T]{
+		config:     config,
+		ttl:        ttl,
+		bufferSize: bufferSize,
+		topics:     make(map[string]map[uint64]*subscription[T]),
+		quitChan:   make(chan struct{}),
+		clock:      newCoarseClock(config),
+	}
+
+	startCleaner(config, tm.quitChan, tm.removeExpired)
+
+	return tm
+}
+
+// Subscribe creates new subscription to the topic. If the map is discarded, returned subscription has closed channel.
+func (m *topicMap[T]) Subscribe(topic string) Subscription[T] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lastID++
+	s := &subscription[T]{
+		tm:        m,
+		id:        m.lastID,
+		topic:     topic,
+		ch:        make(chan T, m.bufferSize),
+		expiredAt: m.clock.expiry(m.ttl),
+	}
+	if m.closed {
+		close(s.ch)
+		return s
+	}
+	subs, found := m.topics[topic]
+	if !found {
+		subs = make(map[uint64]*subscription[T])
+		m.topics[topic] = subs
+	}
+	subs[s.id] = s
+	return s
+}
+
+// Publish sends message to all subscribers of the topic and returns number of subscribers which received it. Message
+// is not sent to subscribers with full channel.
+func (m *topicMap[T]) Publish(topic string, msg T) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var count int
+	for _, s := range m.topics[topic] {
+		select {
+		case s.ch <- msg:
+			s.expiredAt = m.clock.expiry(m.ttl)
+			count++
+		default:
+		}
+	}
+	return count
+}
+
+// Topics returns topics with at least one subscription.
+func (m *topicMap[T]) Topics() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make([]string, 0, len(m.topics))
+	for topic := range m.topics {
+		result = append(result, topic)
+	}
+	return result
+}
+
+// Discard method stops the goroutine for removing subscriptions and closes channels of all subscriptions.
+func (m *topicMap[T]) Discard() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return
+	}
+	m.closed = true
+	close(m.quitChan)
+	m.clock.stop()
+	for _, subs := range m.topics {
+		for _, s := range subs {
+			m.remove(s)
+		}
+	}
+}
+
+// remove removes subscription and closes its channel. It must be called with locked mutex.
+func (m *topicMap[T]) remove(s *subscription[T]) {
+	subs, found := m.topics[s.topic]
+	if !found || subs[s.id] != s {
+		return
+	}
+	close(s.ch)
+	delete(subs, s.id)
+	if len(subs) == 0 {
+		delete(m.topics, s.topic)
+	}
+}
+
+// removeExpired method removes expired subscriptions.
+func (m *topicMap[T]) removeExpired() {
+	m.suspend.handle(m.config, &m.mu, m.shiftExpirations)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.clock.Now()
+	for _, subs := range m.topics {
+		for _, s := range subs {
+			if s.expiredAt.Before(now) {
+				m.remove(s)
+			}
+		}
+	}
+}
+
+// shiftExpirations moves expirations of all subscriptions by d. It must be called with locked mutex.
+func (m *topicMap[T]) shiftExpirations(d time.Duration) {
+	for _, subs := range m.topics {
+		for _, s := range subs {
+			s.expiredAt = shiftExpiry(s.expiredAt, d)
+		}
+	}
+}
+
+func (s *subscription[T]) C() <-chan T {
+	return s.ch
+}
+
+func (s *subscription[T]) Touch() {
+	s.tm.mu.Lock()
+	defer s.tm.mu.Unlock()
+	s.expiredAt = s.tm.clock.expiry(s.tm.ttl)
+}
+
+func (s *subscription[T]) Unsubscribe() {
+	s.tm.mu.Lock()
+	defer s.tm.mu.Unlock()
+	s.tm.remove(s)
+}
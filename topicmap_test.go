@@ -0,0 +1,63 @@
+package gocollections
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTopicMap(t *testing.T) {
+	t.Parallel()
+
+	tm := NewTopicMap[string](100*time.Millisecond, 10, Config{
+		CleanJobInterval: 50 * time.Millisecond,
+	})
+	defer tm.Discard()
+
+	sub1 := tm.Subscribe("news")
+	sub2 := tm.Subscribe("news")
+	idle := tm.Subscribe("sport")
+
+	if n := tm.Publish("news", "hello"); n != 2 {
+		t.Errorf("want: %d, got: %d", 2, n)
+	}
+	if msg := <-sub1.C(); msg != "hello" {
+		t.Errorf("want: %s, got: %s", "hello", msg)
+	}
+
+	sub2.Unsubscribe()
+	if n := tm.Publish("news", "hello again"); n != 1 {
+		t.Errorf("want: %d, got: %d", 1, n)
+	}
+
+	// Idle subscription expires and its channel is closed.
+	select {
+	case _, ok := <-idle.C():
+		if ok {
+			t.Error("Expect closed channel of idle subscription")
+		}
+	case <-time.After(1 * time.Second):
+		t.Error("Idle subscription didn't expire in timeout")
+	}
+	if len(tm.Topics()) > 1 {
+		t.Errorf("Expect topic of expired subscription removed, but got %v", tm.Topics())
+	}
+}
+
+func TestTopicMap_Suspend(t *testing.T) {
+	t.Parallel()
+
+	tm := NewTopicMap[string](10*time.Second, 10, Config{SuspendPolicy: SuspendExpire, TimeResolution: time.Millisecond})
+	defer tm.Discard()
+	sub := tm.Subscribe("news")
+
+	// Simulate expiring of subscriptions lapsed during a minute of suspend.
+	m := tm.(*topicMap[string])
+	m.mu.Lock()
+	m.shiftExpirations(m.config.suspendShift(time.Minute))
+	m.mu.Unlock()
+	m.removeExpired()
+
+	if _, ok := <-sub.C(); ok {
+		t.Error("Expect subscription lapsed during suspend removed")
+	}
+}
@@ -0,0 +1,133 @@
+package gocollections
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+/*
+Top-K
+*/
+
+// TopK tracks the K most frequent keys with space-saving algorithm. Counts decay in time with half-life, so old
+// occurrences have smaller weight than recent ones, ex. for trending items in the last hour.
+type TopK[K comparable] interface {
+	Incr(key K)
+	Add(key K, count float64)
+	Top() []TopKEntry[K]
+	Clear()
+}
+
+// TopKEntry is a key with its decayed count. Error is maximum overestimation of the count caused by space-saving
+// algorithm.
+type TopKEntry[K comparable] struct {
+	Key   K
+	Count float64
+	Error float64
+}
+
+type topKCounter struct {
+	count float64 // scaled count, see topK.scale
+	err   float64 // scaled error
+}
+
+type topK[K comparable] struct {
+	mu       sync.Mutex
+	k        int
+	capacity int
+	halfLife time.Duration
+	start    time.Time // reference time of scaled counts
+	counters map[K]*topKCounter
+}
+
+// NewTopK creates new TopK which returns k most frequent keys. It tracks 4*k keys to get good precision. If halfLife
+// is 0, counts don't decay.
+func NewTopK[K comparable](k int, halfLife time.Duration) TopK[K] {
+	if k < 1 {
+		k = 1
+	}
+	return &topK[K]{
+		k:        k,
+		capacity: 4 * k,
+		halfLife: halfLife,
+		start:    time.Now(),
+		counters: make(map[K]*topKCounter),
+	}
+}
+
+// Incr increments count of the key by 1.
+func (t *topK[K]) Incr(key K) {
+	t.Add(key, 1)
+}
+
+// Add increments count of the key by count.
+func (t *topK[K]) Add(key K, count float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	scaled := count * t.scale(time.Now())
+	if c, found := t.counters[key]; found {
+		c.count += scaled
+		return
+	}
+	if len(t.counters) < t.capacity {
+		t.counters[key] = &topKCounter{count: scaled}
+		return
+	}
+	// Replace counter with the smallest count, new key inherits its count as error.
+	var minKey K
+	var minCounter *topKCounter
+	for k, c := range t.counters {
+		if minCounter == nil || c.count < minCounter.count {
+			minKey, minCounter = k, c
+		}
+	}
+	delete(t.counters, minKey)
+	t.counters[key] = &topKCounter{count: minCounter.count + scaled, err: minCounter.count}
+}
+
+// Top returns up to k keys with the highest decayed counts, the highest first.
+func (t *topK[K]) Top() []TopKEntry[K] {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	scale := t.scale(time.Now())
+	result := make([]TopKEntry[K], 0, len(t.counters))
+	for key, c := range t.counters {
+		result = append(result, TopKEntry[K]{Key: key, Count: c.count / scale, Error: c.err / scale})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Count > result[j].Count
+	})
+	if len(result) > t.k {
+		result = result[:t.k]
+	}
+	return result
+}
+
+// Clear removes all counts.
+func (t *topK[K]) Clear() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.start = time.Now()
+	t.counters = make(map[K]*topKCounter)
+}
+
+// scale returns weight of the occurrence at time now. Instead of decaying all counts, new occurrences get exponentially
+// growing weight. When the weight is too big, counts are normalized. It must be called with locked mutex.
+func (t *topK[K]) scale(now time.Time) float64 {
+	if t.halfLife <= 0 {
+		return 1
+	}
+	halfLives := float64(now.Sub(t.start)) / float64(t.halfLife)
+	if halfLives > 64 {
+		norm := math.Exp2(halfLives)
+		for _, c := range t.counters {
+			c.count /= norm
+			c.err /= norm
+		}
+		t.start = now
+		halfLives = 0
+	}
+	return math.Exp2(halfLives)
+}
@@ -0,0 +1,49 @@
+package gocollections
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTopK(t *testing.T) {
+	t.Parallel()
+
+	topk := NewTopK[string](2, 0)
+	for i := 0; i < 10; i++ {
+		topk.Incr("a")
+	}
+	for i := 0; i < 5; i++ {
+		topk.Incr("b")
+	}
+	topk.Incr("c")
+	for i := 0; i < 20; i++ {
+		// Many rare keys, which replace each other in counters.
+		topk.Incr(string(rune('d' + i)))
+	}
+
+	top := topk.Top()
+	if len(top) != 2 || top[0].Key != "a" || top[1].Key != "b" {
+		t.Fatalf("Expect top keys [a b], but got %v", top)
+	}
+	if top[0].Count != 10 {
+		t.Errorf("want: %d, got: %f", 10, top[0].Count)
+	}
+}
+
+func TestTopK_Decay(t *testing.T) {
+	t.Parallel()
+
+	topk := NewTopK[string](2, 50*time.Millisecond)
+	topk.Add("old", 10)
+	time.Sleep(100 * time.Millisecond)
+	topk.Add("new", 5)
+
+	top := topk.Top()
+	if len(top) != 2 || top[0].Key != "new" {
+		t.Fatalf("Expect recent key first, but got %v", top)
+	}
+	if math.Abs(top[1].Count-2.5) > 1 {
+		t.Errorf("Expect decayed count about 2.5, but got %f", top[1].Count)
+	}
+}
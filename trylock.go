@@ -0,0 +1,79 @@
+package gocollections
+
+import (
+	"sync"
+	"time"
+)
+
+/*
+Try lock
+*/
+
+// Backoff of tryLock between attempts to lock.
+const (
+	minTryLockBackoff = 10 * time.Microsecond
+	maxTryLockBackoff = 1 * time.Millisecond
+)
+
+// tryLock locks mu if it's not held longer than timeout. It returns false if the lock was not acquired. Between
+// attempts it sleeps with exponential backoff, so waiting goroutines don't burn CPU.
+func tryLock(mu *sync.Mutex, timeout time.Duration) bool {
+	if mu.TryLock() {
+		return true
+	}
+	deadline := time.Now().Add(timeout)
+	backoff := minTryLockBackoff
+	for {
+		left := time.Until(deadline)
+		if left <= 0 {
+			return false
+		}
+		if backoff > left {
+			backoff = left
+		}
+		time.Sleep(backoff)
+		if mu.TryLock() {
+			return true
+		}
+		if backoff *= 2; backoff > maxTryLockBackoff {
+			backoff = maxTryLockBackoff
+		}
+	}
+}
+
+// TryAdd adds element to the map like Add, but it doesn't wait for the lock longer than Config.TryLockTimeout, ex.
+// while cleaning goroutine removes expired elements. It returns ErrBusy if the lock was not acquired, ErrRejected if
+// the element was screened, ErrInvalidTTL if the default duration is out of range and ErrClosed after Discard.
+func (m *timeExpiredMap[K, V]) TryAdd(key K, data V) error {
+	duration, err := m.screenAdd(key, data, m.duration)
+	if err != nil {
+		return err
+	}
+	if !tryLock(&m.mu, m.config.TryLockTimeout) {
+		return ErrBusy
+	}
+	defer m.mu.Unlock()
+	if m.closed {
+		return ErrClosed
+	}
+	m.set(key, data, duration)
+	return nil
+}
+
+// TryGet returns element by key like Get, including early expiration, but it doesn't wait for the lock longer than
+// Config.TryLockTimeout. It returns ErrBusy if the lock was not acquired.
+func (m *timeExpiredMap[K, V]) TryGet(key K) (V, error) {
+	var result V
+	if !tryLock(&m.mu, m.config.TryLockTimeout) {
+		return result, ErrBusy
+	}
+	defer m.mu.Unlock()
+	e, now, err := m.lookup(key)
+	if err != nil {
+		return result, err
+	}
+	if m.config.expiresEarly(e.expiredAt, now) {
+		return result, &ExpiredError{Key: key, ExpiredAt: now}
+	}
+	return e.data, nil
+}
@@ -0,0 +1,39 @@
+package gocollections
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTimeExpiredMap_TryAddTryGet(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](time.Minute, Config{TryLockTimeout: 10 * time.Millisecond})
+	defer tmap.Discard()
+	if err := tmap.TryAdd("key1", "value1"); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := tmap.TryGet("key1"); err != nil || v != "value1" {
+		t.Errorf("want: %s, got: %s, %v", "value1", v, err)
+	}
+
+	// Simulate long cleaning which holds the lock.
+	m := tmap.(*timeExpiredMap[string, string])
+	m.mu.Lock()
+	start := time.Now()
+	if _, err := tmap.TryGet("key1"); !errors.Is(err, ErrBusy) {
+		t.Errorf("want: %v, got: %v", ErrBusy, err)
+	}
+	if err := tmap.TryAdd("key2", "value2"); !errors.Is(err, ErrBusy) {
+		t.Errorf("want: %v, got: %v", ErrBusy, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("try should fail fast, elapsed: %v", elapsed)
+	}
+	m.mu.Unlock()
+
+	if err := tmap.TryAdd("key2", "value2"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package gocollections
+
+/*
+Watching of map elements
+*/
+
+// EventKind is kind of WatchEvent.
+type EventKind int
+
+const (
+	EventAdded   EventKind = iota // New key was added to the map.
+	EventUpdated                  // Value of unexpired key was replaced.
+	EventExpired                  // Element expired and was removed from the map.
+)
+
+// WatchEvent is a change of the map element matching predicate of the watch.
+type WatchEvent[K comparable, V any] struct {
+	Kind  EventKind
+	Key   K
+	Value V
+}
+
+// Watch is a watch of map elements created by WatchWhere.
+type Watch[K comparable, V any] interface {
+	// C returns channel with events. If channel is full then oldest event is removed before new is added. The channel
+	// is closed when the watch is stopped or the map is discarded.
+	C() <-chan WatchEvent[K, V]
+	// Stop removes the watch and closes its channel.
+	Stop()
+}
+
+type watch[K comparable, V any] struct {
+	m    *timeExpiredMap[K, V]
+	pred func(key K, value V) bool
+	ch   chan WatchEvent[K, V]
+}
+
+func (w *watch[K, V]) C() <-chan WatchEvent[K, V] {
+	return w.ch
+}
+
+func (w *watch[K, V]) Stop() {
+	w.m.mu.Lock()
+	defer w.m.mu.Unlock()
+	if _, found := w.m.watches[w]; found {
+		delete(w.m.watches, w)
+		close(w.ch)
+	}
+}
+
+// WatchWhere returns watch with events of elements matching pred. Events are sent when a matching element is added,
+// updated or expires. Channel of the watch has bufferSize, which must be bigger than 0, otherwise no event is sent.
+// Function pred is called with locked map, so it must not call methods of the map.
+func (m *timeExpiredMap[K, V]) WatchWhere(pred func(key K, value V) bool, bufferSize int) Watch[K, V] {
+	w := &watch[K, V]{m: m, pred: pred, ch: make(chan WatchEvent[K, V], bufferSize)}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		close(w.ch)
+		return w
+	}
+	m.watches[w] = struct{}{}
+	return w
+}
+
+// notifyWatches sends event to matching watches. It must be called with locked mutex.
+func (m *timeExpiredMap[K, V]) notifyWatches(kind EventKind, key K, value V) {
+	for w := range m.watches {
+		if w.pred(key, value) {
+			sendDropOldest(w.ch, WatchEvent[K, V]{Kind: kind, Key: key, Value: value})
+		}
+	}
+}
+
+// closeWatches stops all watches. It must be called with locked mutex.
+func (m *timeExpiredMap[K, V]) closeWatches() {
+	for w := range m.watches {
+		delete(m.watches, w)
+		close(w.ch)
+	}
+}
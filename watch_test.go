@@ -0,0 +1,39 @@
+package gocollections
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeExpiredMap_WatchWhere(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](10*time.Second, Config{CleanJobInterval: 10 * time.Millisecond})
+	defer tmap.Discard()
+
+	w := tmap.WatchWhere(func(key string, value string) bool {
+		return strings.HasPrefix(key, "user:")
+	}, 10)
+
+	tmap.AddWithDuration("user:1", "online", 20*time.Millisecond)
+	tmap.Add("session:1", "active")
+	tmap.AddWithDuration("user:1", "away", 20*time.Millisecond)
+
+	want := []EventKind{EventAdded, EventUpdated, EventExpired}
+	for _, kind := range want {
+		select {
+		case e := <-w.C():
+			if e.Kind != kind || e.Key != "user:1" {
+				t.Errorf("Expect event %d of user:1, but got %+v", kind, e)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Expect event %d", kind)
+		}
+	}
+
+	w.Stop()
+	if _, ok := <-w.C(); ok {
+		t.Error("Expect closed channel after Stop")
+	}
+}
@@ -0,0 +1,22 @@
+package gocollections
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+/*
+Probabilistic early expiration
+*/
+
+// expiresEarly returns true if the element should be treated as expired before its expiration by XFetch algorithm
+// configured by Config.EarlyExpirationBeta and Config.RecomputeCost. Probability grows as the element gets closer to
+// its expiration, so one of many callers recomputes hot element before it expires for everyone.
+func (c Config) expiresEarly(expiredAt, now time.Time) bool {
+	if c.EarlyExpirationBeta <= 0 || c.RecomputeCost <= 0 || expiredAt.Equal(noExpiryTime) {
+		return false
+	}
+	gap := float64(c.RecomputeCost) * c.EarlyExpirationBeta * -math.Log(1-rand.Float64())
+	return float64(expiredAt.Sub(now)) <= gap
+}
@@ -0,0 +1,53 @@
+package gocollections
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestConfig_ExpiresEarly(t *testing.T) {
+	t.Parallel()
+
+	config := Config{EarlyExpirationBeta: 1, RecomputeCost: time.Second}
+	now := time.Now()
+	count := func(remaining time.Duration) int {
+		early := 0
+		for i := 0; i < 1000; i++ {
+			if config.expiresEarly(now.Add(remaining), now) {
+				early++
+			}
+		}
+		return early
+	}
+
+	// Probability is exp(-remaining/cost).
+	if early := count(time.Hour); early != 0 {
+		t.Errorf("far expiration should not expire early, early: %d", early)
+	}
+	if early := count(time.Second); early < 250 || early > 500 {
+		t.Errorf("want about 368 early expirations, got: %d", early)
+	}
+	if early := count(0); early != 1000 {
+		t.Errorf("expired element should expire early, early: %d", early)
+	}
+	if (Config{}).expiresEarly(now, now) {
+		t.Error("early expiration should be disabled by default")
+	}
+}
+
+func TestTimeExpiredMap_EarlyExpiration(t *testing.T) {
+	t.Parallel()
+
+	tmap := NewTimeExpiredMap[string, string](time.Second, Config{EarlyExpirationBeta: 1,
+		RecomputeCost: 1000 * time.Hour})
+	defer tmap.Discard()
+	tmap.Add("hot", "value")
+
+	if _, err := tmap.Get("hot"); !errors.Is(err, ErrExpired) {
+		t.Errorf("want: %v, got: %v", ErrExpired, err)
+	}
+	if !tmap.Contains("hot") {
+		t.Error("early expired element should stay in the map")
+	}
+}